@@ -6,6 +6,9 @@ package Github
 
 import (
 	"net/url"
+	"sync"
+
+	"lib.virginia.edu/agita/limiter"
 
 	"github.com/google/go-github/v69/github"
 )
@@ -23,7 +26,8 @@ type Client struct {
 // Internal variables
 // ============================================================================
 
-var mainClient *Client
+var mainClient      *Client
+var mainClientMutex sync.Mutex
 
 // ============================================================================
 // Exported functions
@@ -31,6 +35,8 @@ var mainClient *Client
 
 // The default client used for application objects which do not specify one.
 func MainClient() *Client {
+    mainClientMutex.Lock()
+    defer mainClientMutex.Unlock()
     if mainClient == nil {
         mainClient = NewClient()
     }
@@ -41,7 +47,7 @@ func MainClient() *Client {
 //  NOTE: never returns nil
 func NewClient() *Client {
     token  := authToken()
-    client := github.NewClient(nil).WithAuthToken(token)
+    client := github.NewClient(limiter.Github.Client()).WithAuthToken(token)
     return &Client{ptr: client}
 }
 
@@ -57,6 +57,18 @@ func CreateComment(client *Client, owner, repo string, issue int, src *github.Is
     }
 }
 
+// On GitHub, modify the body of an existing issue comment.
+//  NOTE: returns nil on error
+func EditComment(client *Client, owner, repo string, id int64, src *github.IssueComment) *Comment {
+    if src == nil { panic(ERR_NO_ISSUE_COMMENT) }
+    if com := editComment(client.ptr, owner, repo, id, src); com == nil {
+        return nil
+    } else {
+        repo := NewRepositoryType(client, owner, repo)
+        return NewCommentType(client, repo, com)
+    }
+}
+
 // On GitHub, delete an issue comment object.
 //  NOTE: this is only supported during testing
 func DeleteComment(client *Client, owner, repo string, commentId int64) {
@@ -64,6 +76,19 @@ func DeleteComment(client *Client, owner, repo string, commentId int64) {
     deleteComment(client.ptr, owner, repo, commentId)
 }
 
+// ============================================================================
+// Exported methods - editing
+// ============================================================================
+
+// On GitHub, replace the text of the comment.
+//  NOTE: returns nil on error
+func (c *Comment) EditFrom(text string) *Comment {
+    src   := &github.IssueComment{Body: &text}
+    owner := c.repo.Owner
+    repo  := c.repo.Name
+    return EditComment(c.client, owner, repo, c.ID(), src)
+}
+
 // ============================================================================
 // Exported methods - properties
 // ============================================================================
@@ -80,6 +105,12 @@ func (c *Comment) ID() int64 {
     return *c.ptr.ID
 }
 
+// Return the underlying CreatedAt value or nilTime.
+func (c *Comment) CreatedAt() Time {
+    if noComment(c) || NilTime(c.ptr.CreatedAt) { return nilTime }
+    return *c.ptr.CreatedAt
+}
+
 // ============================================================================
 // Internal functions
 // ============================================================================
@@ -21,8 +21,7 @@ import (
 // From GitHub, get the indicated issue comment object.
 //  NOTE: returns nil on error
 func getComment(client *github.Client, owner, repo string, id int64) *github.IssueComment {
-    res, rsp, err := client.Issues.GetComment(ctx, owner, repo, id)
-    extractRateLimit(rsp)
+    res, _, err := client.Issues.GetComment(ctx, owner, repo, id)
     log.ErrorValue(err)
     return res
 }
@@ -30,16 +29,22 @@ func getComment(client *github.Client, owner, repo string, id int64) *github.Iss
 // On GitHub, create a comment object associated with the indicated issue.
 //  NOTE: returns nil on error
 func createComment(client *github.Client, owner, repo string, issue int, src *github.IssueComment) *github.IssueComment {
-    res, rsp, err := client.Issues.CreateComment(ctx, owner, repo, issue, src)
-    extractRateLimit(rsp)
+    res, _, err := client.Issues.CreateComment(ctx, owner, repo, issue, src)
+    log.ErrorValue(err)
+    return res
+}
+
+// On GitHub, modify an issue comment object.
+//  NOTE: returns nil on error
+func editComment(client *github.Client, owner, repo string, id int64, src *github.IssueComment) *github.IssueComment {
+    res, _, err := client.Issues.EditComment(ctx, owner, repo, id, src)
     log.ErrorValue(err)
     return res
 }
 
 // On GitHub, delete an issue comment object.
 func deleteComment(client *github.Client, owner, repo string, commentId int64) {
-    rsp, err := client.Issues.DeleteComment(ctx, owner, repo, commentId)
-    extractRateLimit(rsp)
+    _, err := client.Issues.DeleteComment(ctx, owner, repo, commentId)
     log.ErrorValue(err)
 }
 
@@ -152,6 +152,58 @@ func TestCreateComment(t *testing.T) {
 	}
 }
 
+func TestEditComment(t *testing.T) {
+	const fn = "EditComment"
+	if test.Passive(fn, t) { return }
+
+	type args struct {
+		client *Client
+		owner  string
+		repo   string
+		id     int64
+		src    *github.IssueComment
+	}
+	type testCase struct {
+		name string
+		args args
+		want *Comment
+		err  string
+	}
+
+	client := TestClient
+	repo   := GetFakeRepo(client)
+	issue  := GetFakeIssue(repo)
+	Case   := func(idx int, id int64, text, err string) (tc testCase) {
+		tc.name = test.CaseName(fn, idx)
+		tc.args = args{client, repo.Owner, repo.Name, id, nil}
+		tc.err  = err
+		if text != "" {
+			body := test.Unique(text, tc.name)
+			com  := testGithubComment(body)
+			tc.args.src = com
+			if err == "" {
+				tc.want = &Comment{ptr: com, client: client}
+			}
+		}
+		return
+	}
+
+	com   := GetFakeComment(issue).ID()
+	body  := FAKE_COMMENT_BODY
+	tests := []testCase{
+		Case(0, com, "",   ERR_NO_ISSUE_COMMENT),
+		Case(1, 0,   body, ERR_NOT_FOUND),
+		Case(2, com, body, ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer test.EvaluatePanic(tt.name, tt.err, t)
+			got := EditComment(tt.args.client, tt.args.owner, tt.args.repo, tt.args.id, tt.args.src)
+            testVerifyComment(fn, got, tt.want, t)
+		})
+	}
+}
+
 func TestDeleteComment(t *testing.T) {
 	const fn = "DeleteComment"
 	if test.Passive(fn, t) { return }
@@ -2,6 +2,23 @@
 
 package Github
 
+import (
+	"fmt"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// The error for a file which is too large to be stored in a repository
+// through the GitHub API.
+type FileTooLargeError struct {
+    Repo    string
+    File    string
+    Size    int64
+    Limit   int64
+}
+
 // ============================================================================
 // Exported constants
 // ============================================================================
@@ -29,3 +46,11 @@ const (
     ERR_INVALID_CREATE      = "Validation Failed: "
     ERR_INVALID_IMPORT      = "Validation Failed: ; "
 )
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+func (e *FileTooLargeError) Error() string {
+    return fmt.Sprintf("%s: file %q is %d bytes; the GitHub limit is %d bytes", e.Repo, e.File, e.Size, e.Limit)
+}
@@ -17,6 +17,9 @@ import (
 // Apparently the most GitHub will return per page.
 const MAX_PER_PAGE = 100
 
+// The root of GitHub web pages.
+const WEB_URL = "https://github.com/"
+
 // All target repos begin with "https://github.com/${ORG}".
 const ORG = "uvalib"
 
@@ -8,8 +8,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
+	"lib.virginia.edu/agita/limiter"
 	"lib.virginia.edu/agita/util"
 
 	"github.com/shurcooL/githubv4"
@@ -28,6 +32,81 @@ const gqlABORT = false
 // ============================================================================
 
 var gql_client *githubv4.Client
+var gql_mutex  sync.Mutex
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// Input to the closeIssue mutation.
+//  NOTE: githubv4.CloseIssueInput lacks the "duplicate" fields.
+type CloseIssueInput struct {
+    IssueID          githubv4.ID `json:"issueId"`
+    StateReason      string      `json:"stateReason,omitempty"`
+    DuplicateIssueID githubv4.ID `json:"duplicateIssueId,omitempty"`
+}
+
+// Input to the addSubIssue mutation.
+//  NOTE: githubv4 does not define AddSubIssueInput.
+type AddSubIssueInput struct {
+    IssueID    githubv4.ID `json:"issueId"`
+    SubIssueID githubv4.ID `json:"subIssueId"`
+}
+
+// Input to the createIssueType mutation.
+//  NOTE: githubv4 does not define CreateIssueTypeInput.
+type CreateIssueTypeInput struct {
+    OwnerID     githubv4.ID `json:"ownerId"`
+    Name        string      `json:"name"`
+    Description string      `json:"description,omitempty"`
+    Color       string      `json:"color,omitempty"`
+    IsEnabled   bool        `json:"isEnabled"`
+}
+
+// Input to the updateIssueIssueType mutation.
+//  NOTE: githubv4 does not define UpdateIssueIssueTypeInput.
+type UpdateIssueIssueTypeInput struct {
+    IssueID     githubv4.ID `json:"issueId"`
+    IssueTypeID githubv4.ID `json:"issueTypeId"`
+}
+
+// Input to the createProjectV2Field mutation.
+//  NOTE: githubv4.CreateProjectV2FieldInput lacks iteration fields.
+type CreateProjectV2FieldInput struct {
+    ProjectID              githubv4.ID                 `json:"projectId"`
+    DataType               string                      `json:"dataType"`
+    Name                   string                      `json:"name"`
+    IterationConfiguration *IterationConfigurationInput `json:"iterationConfiguration,omitempty"`
+}
+
+// Input to the updateProjectV2Field mutation.
+//  NOTE: githubv4 does not define UpdateProjectV2FieldInput.
+type UpdateProjectV2FieldInput struct {
+    FieldID                githubv4.ID                  `json:"fieldId"`
+    SingleSelectOptions    []SingleSelectOptionInput    `json:"singleSelectOptions,omitempty"`
+    IterationConfiguration *IterationConfigurationInput `json:"iterationConfiguration,omitempty"`
+}
+
+// The iterations of an iteration field.
+type IterationConfigurationInput struct {
+    StartDate  string           `json:"startDate"`
+    Duration   int              `json:"duration"`
+    Iterations []IterationInput `json:"iterations"`
+}
+
+// An iteration of an iteration field.
+type IterationInput struct {
+    Title     string `json:"title"`
+    StartDate string `json:"startDate"`
+    Duration  int    `json:"duration"`
+}
+
+// An option of a single-select field.
+type SingleSelectOptionInput struct {
+    Name        string `json:"name"`
+    Color       string `json:"color"`
+    Description string `json:"description"`
+}
 
 // ============================================================================
 // Exported functions
@@ -46,6 +125,492 @@ func GqlDeleteIssue(issueNodeId string) bool {
     return gqlMutate(&Mutation, input)
 }
 
+// Close the indicated issue by its unique node ID with the given state reason.
+func GqlCloseIssue(issueNodeId, stateReason string) bool {
+    var Mutation struct {
+        CloseIssue struct {
+            ClientMutationID string
+        } `graphql:"closeIssue(input: $input)"`
+    }
+    input := CloseIssueInput{
+        IssueID:     githubv4.ID(issueNodeId),
+        StateReason: stateReason,
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Close the indicated issue as a duplicate of another issue, both given by
+// their unique node IDs.
+func GqlCloseAsDuplicate(issueNodeId, duplicateOfNodeId string) bool {
+    var Mutation struct {
+        CloseIssue struct {
+            ClientMutationID string
+        } `graphql:"closeIssue(input: $input)"`
+    }
+    input := CloseIssueInput{
+        IssueID:          githubv4.ID(issueNodeId),
+        StateReason:      "DUPLICATE",
+        DuplicateIssueID: githubv4.ID(duplicateOfNodeId),
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Make an issue a sub-issue of a parent issue, both given by their unique node
+// IDs.
+func GqlAddSubIssue(parentNodeId, childNodeId string) bool {
+    var Mutation struct {
+        AddSubIssue struct {
+            ClientMutationID string
+        } `graphql:"addSubIssue(input: $input)"`
+    }
+    input := AddSubIssueInput{
+        IssueID:    githubv4.ID(parentNodeId),
+        SubIssueID: githubv4.ID(childNodeId),
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Get the unique node ID of an organization and its issue types.
+//  NOTE: returns a blank ID on error.
+func GqlIssueTypes(org string) (string, []IssueType) {
+    var Query struct {
+        Organization struct {
+            ID          string
+            IssueTypes  struct {
+                Nodes []IssueType
+            } `graphql:"issueTypes(first: 100)"`
+        } `graphql:"organization(login: $login)"`
+    }
+    vars := map[string]any{"login": githubv4.String(org)}
+    if !gqlQuery(&Query, vars) {
+        return "", nil
+    }
+    return Query.Organization.ID, Query.Organization.IssueTypes.Nodes
+}
+
+// Create an issue type for the organization given by its unique node ID.
+//  NOTE: returns the new issue type with a blank ID on error.
+func GqlCreateIssueType(orgNodeId string, issueType IssueType) IssueType {
+    var Mutation struct {
+        CreateIssueType struct {
+            IssueType IssueType
+        } `graphql:"createIssueType(input: $input)"`
+    }
+    input := CreateIssueTypeInput{
+        OwnerID:     githubv4.ID(orgNodeId),
+        Name:        issueType.Name,
+        Description: issueType.Description,
+        Color:       issueType.Color,
+        IsEnabled:   true,
+    }
+    if !gqlMutate(&Mutation, input) {
+        issueType.ID = ""
+        return issueType
+    }
+    return Mutation.CreateIssueType.IssueType
+}
+
+// Set the issue type of an issue, both given by their unique node IDs.
+func GqlUpdateIssueType(issueNodeId, issueTypeNodeId string) bool {
+    var Mutation struct {
+        UpdateIssueIssueType struct {
+            ClientMutationID string
+        } `graphql:"updateIssueIssueType(input: $input)"`
+    }
+    input := UpdateIssueIssueTypeInput{
+        IssueID:     githubv4.ID(issueNodeId),
+        IssueTypeID: githubv4.ID(issueTypeNodeId),
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// ============================================================================
+// Exported functions - members
+// ============================================================================
+
+// Get the email addresses of organization members in the organization's
+// verified domains, by member login.
+//  NOTE: requires an organization owner token; returns partial results on
+//  error.
+func GqlVerifiedEmails(org string) map[string][]string {
+    var Query struct {
+        Organization struct {
+            MembersWithRole struct {
+                Nodes []struct {
+                    Login  string
+                    Emails []string `graphql:"organizationVerifiedDomainEmails(login: $login)"`
+                }
+                PageInfo struct {
+                    HasNextPage bool
+                    EndCursor   string
+                }
+            } `graphql:"membersWithRole(first: 100, after: $cursor)"`
+        } `graphql:"organization(login: $login)"`
+    }
+    vars := map[string]any{
+        "login":  githubv4.String(org),
+        "cursor": (*githubv4.String)(nil),
+    }
+    res := map[string][]string{}
+    for gqlQuery(&Query, vars) {
+        members := Query.Organization.MembersWithRole
+        for _, node := range members.Nodes {
+            if len(node.Emails) > 0 {
+                res[node.Login] = node.Emails
+            }
+        }
+        if !members.PageInfo.HasNextPage {
+            break
+        }
+        vars["cursor"] = githubv4.NewString(githubv4.String(members.PageInfo.EndCursor))
+    }
+    return res
+}
+
+// ============================================================================
+// Exported functions - projects
+// ============================================================================
+
+// Get the unique node ID of an organization and its projects whose titles
+// match the query.
+//  NOTE: returns a blank ID on error.
+func GqlProjectsV2(org, query string) (string, []ProjectV2) {
+    var Query struct {
+        Organization struct {
+            ID          string
+            ProjectsV2  struct {
+                Nodes []ProjectV2
+            } `graphql:"projectsV2(first: 100, query: $query)"`
+        } `graphql:"organization(login: $login)"`
+    }
+    vars := map[string]any{
+        "login": githubv4.String(org),
+        "query": githubv4.String(query),
+    }
+    if !gqlQuery(&Query, vars) {
+        return "", nil
+    }
+    return Query.Organization.ID, Query.Organization.ProjectsV2.Nodes
+}
+
+// Get the unique node ID of a repository.
+//  NOTE: returns a blank ID on error.
+func GqlRepositoryID(owner, repo string) string {
+    var Query struct {
+        Repository struct {
+            ID string
+        } `graphql:"repository(owner: $owner, name: $name)"`
+    }
+    vars := map[string]any{
+        "owner": githubv4.String(owner),
+        "name":  githubv4.String(repo),
+    }
+    if !gqlQuery(&Query, vars) {
+        return ""
+    }
+    return Query.Repository.ID
+}
+
+// Create a project for the organization given by its unique node ID, linked
+// to the repository given by its unique node ID (unless it is blank).
+//  NOTE: returns the new project with a blank ID on error.
+func GqlCreateProjectV2(orgNodeId, repoNodeId, title string) ProjectV2 {
+    var Mutation struct {
+        CreateProjectV2 struct {
+            ProjectV2 ProjectV2
+        } `graphql:"createProjectV2(input: $input)"`
+    }
+    input := githubv4.CreateProjectV2Input{
+        OwnerID: githubv4.ID(orgNodeId),
+        Title:   githubv4.String(title),
+    }
+    if repoNodeId != "" {
+        input.RepositoryID = githubv4.NewID(repoNodeId)
+    }
+    if !gqlMutate(&Mutation, input) {
+        return ProjectV2{Title: title}
+    }
+    return Mutation.CreateProjectV2.ProjectV2
+}
+
+// Get the single-select and iteration fields of a project given by its unique
+// node ID.
+//  NOTE: returns an empty result on error.
+func GqlProjectV2Fields(projectNodeId string) []ProjectField {
+    type option struct {
+        ID    string
+        Name  string
+    }
+    type iteration struct {
+        ID    string
+        Title string
+    }
+    var Query struct {
+        Node struct {
+            ProjectV2 struct {
+                Fields struct {
+                    Nodes []struct {
+                        SingleSelect struct {
+                            ID      string
+                            Name    string
+                            Options []option
+                        } `graphql:"... on ProjectV2SingleSelectField"`
+                        Iteration struct {
+                            ID            string
+                            Name          string
+                            Configuration struct {
+                                Iterations          []iteration
+                                CompletedIterations []iteration
+                            }
+                        } `graphql:"... on ProjectV2IterationField"`
+                        Plain struct {
+                            ID            string
+                            Name          string
+                        } `graphql:"... on ProjectV2Field"`
+                    }
+                } `graphql:"fields(first: 100)"`
+            } `graphql:"... on ProjectV2"`
+        } `graphql:"node(id: $id)"`
+    }
+    vars := map[string]any{"id": githubv4.ID(projectNodeId)}
+    if !gqlQuery(&Query, vars) {
+        return []ProjectField{}
+    }
+    res := []ProjectField{}
+    for _, node := range Query.Node.ProjectV2.Fields.Nodes {
+        if node.SingleSelect.ID != "" {
+            field := ProjectField{ID: node.SingleSelect.ID, Name: node.SingleSelect.Name, Options: map[string]string{}}
+            for _, opt := range node.SingleSelect.Options {
+                field.Options[opt.Name] = opt.ID
+            }
+            res = append(res, field)
+        } else if node.Iteration.ID != "" {
+            field := ProjectField{ID: node.Iteration.ID, Name: node.Iteration.Name, Options: map[string]string{}}
+            config := node.Iteration.Configuration
+            for _, iter := range slices.Concat(config.CompletedIterations, config.Iterations) {
+                field.Options[iter.Title] = iter.ID
+            }
+            res = append(res, field)
+        } else if node.Plain.ID != "" {
+            field := ProjectField{ID: node.Plain.ID, Name: node.Plain.Name, Options: map[string]string{}}
+            res = append(res, field)
+        }
+    }
+    return res
+}
+
+// Replace the options of a single-select field given by its unique node ID.
+func GqlSetFieldOptions(fieldNodeId string, options []SingleSelectOptionInput) bool {
+    var Mutation struct {
+        UpdateProjectV2Field struct {
+            ClientMutationID string
+        } `graphql:"updateProjectV2Field(input: $input)"`
+    }
+    input := UpdateProjectV2FieldInput{
+        FieldID:             githubv4.ID(fieldNodeId),
+        SingleSelectOptions: options,
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Replace the iterations of an iteration field given by its unique node ID.
+func GqlSetFieldIterations(fieldNodeId string, config IterationConfigurationInput) bool {
+    var Mutation struct {
+        UpdateProjectV2Field struct {
+            ClientMutationID string
+        } `graphql:"updateProjectV2Field(input: $input)"`
+    }
+    input := UpdateProjectV2FieldInput{
+        FieldID:                githubv4.ID(fieldNodeId),
+        IterationConfiguration: &config,
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Create an iteration field for a project given by its unique node ID.
+func GqlCreateIterationField(projectNodeId, name string, config IterationConfigurationInput) bool {
+    var Mutation struct {
+        CreateProjectV2Field struct {
+            ClientMutationID string
+        } `graphql:"createProjectV2Field(input: $input)"`
+    }
+    input := CreateProjectV2FieldInput{
+        ProjectID:              githubv4.ID(projectNodeId),
+        DataType:               "ITERATION",
+        Name:                   name,
+        IterationConfiguration: &config,
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Create a field for a project given by its unique node ID, where `dataType`
+// is "TEXT", "NUMBER" or "DATE".
+func GqlCreateProjectV2Field(projectNodeId, name, dataType string) bool {
+    var Mutation struct {
+        CreateProjectV2Field struct {
+            ClientMutationID string
+        } `graphql:"createProjectV2Field(input: $input)"`
+    }
+    input := CreateProjectV2FieldInput{
+        ProjectID: githubv4.ID(projectNodeId),
+        DataType:  dataType,
+        Name:      name,
+    }
+    return gqlMutate(&Mutation, input)
+}
+// Get the items of a project given by its unique node ID, in project order,
+// with the values of their fields by field name.
+//  NOTE: items which are not issues are included with a blank Repo.
+//  NOTE: returns nil on error.
+func GqlProjectV2Items(projectNodeId string) []ProjectItem {
+    type field struct {
+        Common struct {
+            Name string
+        } `graphql:"... on ProjectV2FieldCommon"`
+    }
+    var Query struct {
+        Node struct {
+            ProjectV2 struct {
+                Items struct {
+                    PageInfo struct {
+                        HasNextPage bool
+                        EndCursor   githubv4.String
+                    }
+                    Nodes []struct {
+                        ID      string
+                        Content struct {
+                            Issue struct {
+                                Number     int
+                                Repository struct {
+                                    Name string
+                                }
+                            } `graphql:"... on Issue"`
+                        }
+                        FieldValues struct {
+                            Nodes []struct {
+                                SingleSelect struct {
+                                    Name  string
+                                    Field field
+                                } `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+                                Iteration struct {
+                                    Title string
+                                    Field field
+                                } `graphql:"... on ProjectV2ItemFieldIterationValue"`
+                                Text struct {
+                                    Text  string
+                                    Field field
+                                } `graphql:"... on ProjectV2ItemFieldTextValue"`
+                                Number struct {
+                                    Number float64
+                                    Field  field
+                                } `graphql:"... on ProjectV2ItemFieldNumberValue"`
+                                Date struct {
+                                    Date  string
+                                    Field field
+                                } `graphql:"... on ProjectV2ItemFieldDateValue"`
+                            }
+                        } `graphql:"fieldValues(first: 50)"`
+                    }
+                } `graphql:"items(first: 100, after: $cursor, orderBy: {field: POSITION, direction: ASC})"`
+            } `graphql:"... on ProjectV2"`
+        } `graphql:"node(id: $id)"`
+    }
+    vars := map[string]any{
+        "id":     githubv4.ID(projectNodeId),
+        "cursor": (*githubv4.String)(nil),
+    }
+    res := []ProjectItem{}
+    for {
+        if !gqlQuery(&Query, vars) {
+            return nil
+        }
+        items := Query.Node.ProjectV2.Items
+        for _, node := range items.Nodes {
+            item := ProjectItem{
+                ID:     node.ID,
+                Repo:   node.Content.Issue.Repository.Name,
+                Number: node.Content.Issue.Number,
+                Values: map[string]string{},
+            }
+            for _, value := range node.FieldValues.Nodes {
+                switch {
+                    case value.SingleSelect.Field.Common.Name != "":
+                        item.Values[value.SingleSelect.Field.Common.Name] = value.SingleSelect.Name
+                    case value.Iteration.Field.Common.Name != "":
+                        item.Values[value.Iteration.Field.Common.Name] = value.Iteration.Title
+                    case value.Text.Field.Common.Name != "":
+                        item.Values[value.Text.Field.Common.Name] = value.Text.Text
+                    case value.Number.Field.Common.Name != "":
+                        item.Values[value.Number.Field.Common.Name] = strconv.FormatFloat(value.Number.Number, 'f', -1, 64)
+                    case value.Date.Field.Common.Name != "":
+                        item.Values[value.Date.Field.Common.Name] = value.Date.Date
+                }
+            }
+            res = append(res, item)
+        }
+        if !items.PageInfo.HasNextPage {
+            return res
+        }
+        vars["cursor"] = githubv4.NewString(items.PageInfo.EndCursor)
+    }
+}
+
+// Add an issue to a project, both given by their unique node IDs.
+//  NOTE: returns the unique node ID of the project item or blank on error.
+//  NOTE: an issue which is already in the project is not added again.
+func GqlAddProjectV2Item(projectNodeId, issueNodeId string) string {
+    var Mutation struct {
+        AddProjectV2ItemById struct {
+            Item struct {
+                ID string
+            }
+        } `graphql:"addProjectV2ItemById(input: $input)"`
+    }
+    input := githubv4.AddProjectV2ItemByIdInput{
+        ProjectID: githubv4.ID(projectNodeId),
+        ContentID: githubv4.ID(issueNodeId),
+    }
+    if !gqlMutate(&Mutation, input) {
+        return ""
+    }
+    return Mutation.AddProjectV2ItemById.Item.ID
+}
+
+// Move a project item after another item (or to the top if `afterNodeId` is
+// blank), all given by their unique node IDs.
+func GqlMoveProjectV2Item(projectNodeId, itemNodeId, afterNodeId string) bool {
+    var Mutation struct {
+        UpdateProjectV2ItemPosition struct {
+            ClientMutationID string
+        } `graphql:"updateProjectV2ItemPosition(input: $input)"`
+    }
+    input := githubv4.UpdateProjectV2ItemPositionInput{
+        ProjectID: githubv4.ID(projectNodeId),
+        ItemID:    githubv4.ID(itemNodeId),
+    }
+    if afterNodeId != "" {
+        input.AfterID = githubv4.NewID(afterNodeId)
+    }
+    return gqlMutate(&Mutation, input)
+}
+
+// Set the value of a single-select or iteration field of a project item, all
+// given by their unique node IDs.
+func GqlSetProjectV2ItemField(projectNodeId, itemNodeId, fieldNodeId string, value githubv4.ProjectV2FieldValue) bool {
+    var Mutation struct {
+        UpdateProjectV2ItemFieldValue struct {
+            ClientMutationID string
+        } `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
+    }
+    input := githubv4.UpdateProjectV2ItemFieldValueInput{
+        ProjectID: githubv4.ID(projectNodeId),
+        ItemID:    githubv4.ID(itemNodeId),
+        FieldID:   githubv4.ID(fieldNodeId),
+        Value:     value,
+    }
+    return gqlMutate(&Mutation, input)
+}
+
 // ============================================================================
 // Internal functions
 // ============================================================================
@@ -66,6 +631,8 @@ func gqlQuery(query any, variables ...map[string]any) bool {
 
 // The current GraphQL client.
 func gqlClient() *githubv4.Client {
+    gql_mutex.Lock()
+    defer gql_mutex.Unlock()
     if gql_client == nil {
         gql_client = gqlConnect()
     }
@@ -76,7 +643,8 @@ func gqlClient() *githubv4.Client {
 func gqlConnect() *githubv4.Client {
     tok := oauth2.Token{AccessToken: authToken()}
 	src := oauth2.StaticTokenSource(&tok)
-	cli := oauth2.NewClient(context.Background(), src)
+    ctx := context.WithValue(context.Background(), oauth2.HTTPClient, limiter.Github.Client())
+	cli := oauth2.NewClient(ctx, src)
     return githubv4.NewClient(cli)
 }
 
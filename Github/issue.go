@@ -47,6 +47,16 @@ func NewIssueType(client *Client, owner, repo string, issue *github.Issue) *Issu
     }
 }
 
+// The web URL of the indicated repository issue.
+func IssueURL(owner, repo string, number int) string {
+    return fmt.Sprintf("%s%s/%s/issues/%d", WEB_URL, OrgOwner(owner), repo, number)
+}
+
+// The web URL of the indicated issue comment.
+func CommentURL(owner, repo string, number int, id int64) string {
+    return fmt.Sprintf("%s#issuecomment-%d", IssueURL(owner, repo, number), id)
+}
+
 // On GitHub, create a new issue on the indicated repository.
 //  NOTE: returns 0 if finished; returns the import request ID otherwise.
 func ImportIssue(client *Client, owner, repo string, imp *IssueImport, comments ...*CommentImport) int {
@@ -77,6 +87,16 @@ func GetIssue(client *Client, owner, repo string, number int) *Issue {
     }
 }
 
+// On GitHub, modify the indicated repository issue.
+//  NOTE: returns nil on error
+func EditIssue(client *Client, owner, repo string, number int, req *github.IssueRequest) *Issue {
+    if issue := editIssue(client.ptr, owner, repo, number, req); issue == nil {
+        return nil
+    } else {
+        return NewIssueType(client, owner, repo, issue)
+    }
+}
+
 // Fetch all issues from GitHub for the indicated repository.
 //  NOTE: if an error was encountered a partial list may be returned
 func GetIssues(client *Client, owner, repo string) []*Issue {
@@ -92,6 +112,19 @@ func GetIssues(client *Client, owner, repo string) []*Issue {
     return result
 }
 
+// Close the indicated repository issue (or change the reason that it was
+// closed) with a GraphQL state reason like "NOT_PLANNED".
+//  NOTE: returns false on error
+func CloseIssue(client *Client, owner, repo string, number int, stateReason string) bool {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    issue := getIssue(client.ptr, owner, repo, number)
+    if (issue == nil) || (issue.NodeID == nil) {
+        return false
+    }
+    return GqlCloseIssue(*issue.NodeID, stateReason)
+}
+
 // Remove the indicated repository issue from GitHub.
 func DeleteIssue(client *Client, owner, repo string, number int) bool {
     return deleteIssue(client.ptr, owner, repo, number)
@@ -189,6 +222,20 @@ func noIssue(i *Issue) bool {
     return (i == nil) || (i.ptr == nil)
 }
 
+// ============================================================================
+// Exported members - editing
+// ============================================================================
+
+// On GitHub, replace the title and body of the issue.
+//  NOTE: returns nil on error
+func (i *Issue) EditFrom(title, body string) *Issue {
+    req   := &github.IssueRequest{Title: &title, Body: &body}
+    cli   := i.client
+    owner := i.repo.Owner
+    repo  := i.repo.Name
+    return EditIssue(cli, owner, repo, i.Number, req)
+}
+
 // ============================================================================
 // Exported members - comments
 // ============================================================================
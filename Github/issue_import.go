@@ -7,7 +7,10 @@ package Github
 
 import (
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
+	"sync"
 
 	"lib.virginia.edu/agita/log"
 	"lib.virginia.edu/agita/markdown"
@@ -25,10 +28,93 @@ type IssueImport struct {
     github.IssueImport
 }
 
+// The outcome of an issue import request.
+type ImportStatus struct {
+    ID       int
+    Status   string     // "pending", "imported", or "failed"
+    IssueURL string     // Only when Status is "imported".
+    Issue    int        // Issue number extracted from IssueURL.
+    Errors   []ImportError  // Only when Status is "failed".
+}
+
+// A problem reported by GitHub with an issue import request.
+type ImportError struct {
+    Location string     // E.g. "/issue/assignee" or "/comments[2]/body".
+    Resource string     // E.g. "Issue" or "IssueComment".
+    Field    string     // E.g. "assignee" or "body".
+    Value    string
+    Code     string     // E.g. "invalid" or "too_long".
+}
+
+// Render the error as a string.
+func (e ImportError) String() string {
+    parts := []string{}
+    if e.Location != "" { parts = append(parts, e.Location) }
+    if e.Field    != "" { parts = append(parts, e.Field) }
+    if e.Value    != "" { parts = append(parts, fmt.Sprintf("%q", e.Value)) }
+    if e.Code     != "" { parts = append(parts, e.Code) }
+    return strings.Join(parts, " ")
+}
+
+// Render all errors as a single string.
+func (s *ImportStatus) ErrorText() string {
+    if s == nil { return "" }
+    res := make([]string, 0, len(s.Errors))
+    for _, e := range s.Errors {
+        res = append(res, e.String())
+    }
+    return strings.Join(res, "; ")
+}
+
+// Indicate whether the import request has been resolved one way or another.
+func (s *ImportStatus) Done() bool {
+    return (s != nil) && ((s.Status == "imported") || (s.Status == "failed"))
+}
+
+// Indicate whether the import request resulted in a new GitHub issue.
+func (s *ImportStatus) Imported() bool {
+    return (s != nil) && (s.Status == "imported")
+}
+
+// Indicate whether the import request was rejected.
+func (s *ImportStatus) Failed() bool {
+    return (s != nil) && (s.Status == "failed")
+}
+
 // ============================================================================
 // Exported functions
 // ============================================================================
 
+// Get the current status of an issue import request.
+//  NOTE: returns nil if the status could not be acquired.
+func CheckIssueImport(client *Client, owner, repo string, importID int) *ImportStatus {
+    return checkIssueImport(client.ptr, owner, repo, importID)
+}
+
+// Indicate whether the GitHub user can be assigned to issues in the indicated
+// repository.  Definitive answers are cached so that each user is only checked
+// once per repository.
+//  NOTE: a failed check is treated as false but is not cached.
+func IsAssignable(client *Client, owner, repo, user string) bool {
+    if user == "" {
+        return false
+    }
+    key := owner + "/" + repo + ":" + user
+    assignableMutex.Lock()
+    res, found := assignable[key]
+    assignableMutex.Unlock()
+    if found {
+        return res
+    }
+    res, ok := isAssignee(client.ptr, owner, repo, user)
+    if ok {
+        assignableMutex.Lock()
+        assignable[key] = res
+        assignableMutex.Unlock()
+    }
+    return res
+}
+
 // Generate a wrapper for a Github issue import object from a table of field
 // names and values.
 //  NOTE: never returns nil
@@ -103,7 +189,6 @@ func importIssue(client *github.Client, owner, repo string, imp *github.IssueImp
     if imp == nil { panic(ERR_NO_ISSUE_IMPORT) }
     req := NewIssueImportRequest(*imp, comments...).IssueImportRequest
     impRsp, rsp, err := client.IssueImport.Create(ctx, owner, repo, &req)
-    extractRateLimit(rsp)
     pending := IsScheduled(err)
     if pending || (log.ErrorValue(err) == nil) {
         log.Info("\n*** import issue %q - rsp = %v\n", imp.Title, rsp)
@@ -117,14 +202,66 @@ func importIssue(client *github.Client, owner, repo string, imp *github.IssueImp
 
 // Determine whether the import occurred and with what status.
 func checkImportIssue(client *github.Client, owner, repo string, importID int) (done bool, status string) {
-    impRsp, rsp, err := client.IssueImport.CheckStatus(ctx, owner, repo, int64(importID))
-    extractRateLimit(rsp)
-    if !IsScheduled(err) {
-        log.ErrorValue(err)
-    }
-    if (impRsp != nil) && (impRsp.Status != nil) {
-        status = *impRsp.Status
+    if res := checkIssueImport(client, owner, repo, importID); res != nil {
+        status = res.Status
     }
     done = (status == "imported")
     return
 }
+
+// Cached results of IsAssignable().
+var assignable      = map[string]bool{}
+var assignableMutex sync.Mutex
+
+// Indicate whether the user is an assignee for the indicated repository.
+//  NOTE: the second result is false unless GitHub gave a definitive answer
+//  (204 or 404).
+func isAssignee(client *github.Client, owner, repo, user string) (bool, bool) {
+    res, _, err := client.Issues.IsAssignee(ctx, owner, repo, user)
+    return res, (log.ErrorValue(err) == nil)
+}
+
+// Media type required by the issue import API.
+const issueImportMediaType = "application/vnd.github.golden-comet-preview+json"
+
+// The issue import status response.
+//  NOTE: github.IssueImportResponse does not include "issue_url".
+type issueImportStatus struct {
+    ID       *int    `json:"id,omitempty"`
+    Status   *string `json:"status,omitempty"`
+    IssueURL *string `json:"issue_url,omitempty"`
+    Errors   []*github.IssueImportError `json:"errors,omitempty"`
+}
+
+// Get the current status of an issue import request.
+func checkIssueImport(client *github.Client, owner, repo string, importID int) *ImportStatus {
+    url := fmt.Sprintf("repos/%s/%s/import/issues/%d", owner, repo, importID)
+    req, err := client.NewRequest("GET", url, nil)
+    if log.ErrorValue(err) != nil {
+        return nil
+    }
+    req.Header.Set("Accept", issueImportMediaType)
+    impRsp := &issueImportStatus{}
+    _, err = client.Do(ctx, req, impRsp)
+    if !IsScheduled(err) && (log.ErrorValue(err) != nil) {
+        return nil
+    }
+    res := &ImportStatus{ID: importID}
+    if impRsp.Status != nil {
+        res.Status = *impRsp.Status
+    }
+    if impRsp.IssueURL != nil {
+        res.IssueURL = *impRsp.IssueURL
+        res.Issue, _ = strconv.Atoi(path.Base(res.IssueURL))
+    }
+    for _, e := range impRsp.Errors {
+        res.Errors = append(res.Errors, ImportError{
+            Location: e.GetLocation(),
+            Resource: e.GetResource(),
+            Field:    e.GetField(),
+            Value:    e.GetValue(),
+            Code:     e.GetCode(),
+        })
+    }
+    return res
+}
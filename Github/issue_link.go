@@ -0,0 +1,78 @@
+// Github/issue_link.go
+//
+// Relationships between GitHub issues.
+//
+// @see https://docs.github.com/en/rest/issues/issue-dependencies
+// @see https://docs.github.com/en/issues/tracking-your-work-with-issues/using-issues/adding-sub-issues
+
+package Github
+
+import (
+	"fmt"
+
+	"lib.virginia.edu/agita/log"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// On GitHub, record that an issue is blocked by another issue of the same
+// owner, possibly in a different repository.
+//  NOTE: returns false on error
+func AddBlockedBy(client *Client, owner, repo string, number int, blockerRepo string, blocker int) bool {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    issue := getIssue(client.ptr, owner, blockerRepo, blocker)
+    if (issue == nil) || (issue.ID == nil) {
+        return false
+    }
+    return addBlockedBy(client.ptr, owner, repo, number, *issue.ID)
+}
+
+// On GitHub, close an issue as a duplicate of another issue of the same owner,
+// possibly in a different repository.
+//  NOTE: returns false on error
+func CloseAsDuplicate(client *Client, owner, repo string, number int, duplicateOfRepo string, duplicateOf int) bool {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    issue := getIssue(client.ptr, owner, repo, number)
+    other := getIssue(client.ptr, owner, duplicateOfRepo, duplicateOf)
+    if (issue == nil) || (issue.NodeID == nil) || (other == nil) || (other.NodeID == nil) {
+        return false
+    }
+    return GqlCloseAsDuplicate(*issue.NodeID, *other.NodeID)
+}
+
+// On GitHub, make an issue a sub-issue of a parent issue of the same owner,
+// possibly in a different repository.
+//  NOTE: returns false on error
+func AddSubIssue(client *Client, owner, repo string, number int, childRepo string, child int) bool {
+    if client == nil { client = MainClient() }
+    owner  = OrgOwner(owner)
+    parent := getIssue(client.ptr, owner, repo, number)
+    issue  := getIssue(client.ptr, owner, childRepo, child)
+    if (parent == nil) || (parent.NodeID == nil) || (issue == nil) || (issue.NodeID == nil) {
+        return false
+    }
+    return GqlAddSubIssue(*parent.NodeID, *issue.NodeID)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Add a "blocked by" dependency to the indicated issue.
+//  NOTE: `blockerID` is the ID of the blocking issue, not its number.
+func addBlockedBy(client *github.Client, owner, repo string, number int, blockerID int64) bool {
+    url  := fmt.Sprintf("repos/%s/%s/issues/%d/dependencies/blocked_by", owner, repo, number)
+    body := map[string]int64{"issue_id": blockerID}
+    req, err := client.NewRequest("POST", url, body)
+    if log.ErrorValue(err) != nil {
+        return false
+    }
+    _, err = client.Do(ctx, req, nil)
+    return log.ErrorValue(err) == nil
+}
@@ -25,7 +25,6 @@ import (
 func createIssue(client *github.Client, owner, repo string, req *github.IssueRequest) *github.Issue {
     if req == nil { panic(ERR_NO_ISSUE_REQUEST) }
     result, rsp, err := client.Issues.Create(ctx, owner, repo, req)
-    extractRateLimit(rsp)
     if log.ErrorValue(err) == nil {
         log.Info("\n*** create issue %q - rsp = %v\n", *req.Title, rsp)
     }
@@ -34,8 +33,16 @@ func createIssue(client *github.Client, owner, repo string, req *github.IssueReq
 
 // From GitHub, retrieve the indicated repository issue.
 func getIssue(client *github.Client, owner, repo string, number int) *github.Issue {
-    result, rsp, err := client.Issues.Get(ctx, owner, repo, number)
-    extractRateLimit(rsp)
+    result, _, err := client.Issues.Get(ctx, owner, repo, number)
+    log.ErrorValue(err)
+    return result
+}
+
+// On GitHub, modify the indicated repository issue.
+//  NOTE: only the fields present in `req` are changed.
+func editIssue(client *github.Client, owner, repo string, number int, req *github.IssueRequest) *github.Issue {
+    if req == nil { panic(ERR_NO_ISSUE_REQUEST) }
+    result, _, err := client.Issues.Edit(ctx, owner, repo, number, req)
     log.ErrorValue(err)
     return result
 }
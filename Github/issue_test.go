@@ -217,6 +217,59 @@ func TestGetIssue(t *testing.T) {
 	}
 }
 
+func TestEditIssue(t *testing.T) {
+    const fn = "EditIssue"
+    if test.Passive(fn, t) { return }
+
+    type args struct {
+		client *Client
+		owner  string
+		repo   string
+		number int
+		req    *github.IssueRequest
+	}
+    type testCase struct {
+		name string
+		args args
+		want *Issue
+        err  string
+	}
+
+    client := TestClient
+    repo   := GetFakeRepo(client)
+    issue  := GetFakeIssue(repo)
+    Case   := func(idx int, number int, title, body, err string) (tc testCase) {
+        tc.name = test.CaseName(fn, idx)
+        tc.args = args{client, repo.Owner, repo.Name, number, nil}
+        tc.err  = err
+        if (title != "") || (body != "") {
+            title = test.Unique(title, tc.name)
+            body  = test.Unique(body,  tc.name)
+            req  := testIssueRequest(title, body)
+            iss  := &github.Issue{Number: github.Ptr(number), Title: req.Title, Body: req.Body}
+            tc.args.req = req
+            tc.want     = &Issue{Number: number, ptr: iss, client: client}
+        }
+        return
+    }
+
+    number := issue.Number
+    title  := FAKE_ISSUE_TITLE
+    body   := FAKE_ISSUE_BODY
+    tests  := []testCase{
+        Case(0, number, "",    "",   ERR_NO_ISSUE_REQUEST),
+        Case(1, 0,      title, body, ERR_NOT_FOUND),
+        Case(2, number, title, body, ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            defer test.EvaluatePanic(tt.name, tt.err, t)
+            got := EditIssue(tt.args.client, tt.args.owner, tt.args.repo, tt.args.number, tt.args.req)
+            testVerifyIssue(fn, got, tt.want, t)
+		})
+	}
+}
+
 func TestGetIssues(t *testing.T) {
     const fn = "GetIssues"
 
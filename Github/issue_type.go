@@ -0,0 +1,111 @@
+// Github/issue_type.go
+//
+// Organization issue types, which are managed through the GraphQL API.
+//
+// @see https://docs.github.com/en/issues/tracking-your-work-with-issues/configuring-issues/managing-issue-types-in-an-organization
+
+package Github
+
+import (
+	"maps"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A GitHub organization issue type.
+type IssueType struct {
+    ID          string  // Unique node ID assigned by GitHub.
+    Name        string
+    Description string
+    Color       string  // E.g. "GRAY", "BLUE", "GREEN", "YELLOW", "RED".
+    IsEnabled   bool
+}
+
+// ============================================================================
+// Internal variables
+// ============================================================================
+
+// For each organization, its node ID and its issue types by lowercase name.
+var orgNodeIDs = map[string]string{}
+var issueTypes = map[string]map[string]IssueType{}
+var issueTypesMutex sync.Mutex
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Get the issue types of the organization by lowercase name.
+//  NOTE: issue type names are not case-sensitive on GitHub.
+//  NOTE: returns an empty result on error
+func GetIssueTypes(owner string) map[string]IssueType {
+    owner = OrgOwner(owner)
+    issueTypesMutex.Lock()
+    defer issueTypesMutex.Unlock()
+    return maps.Clone(orgIssueTypes(owner))
+}
+
+// Create an issue type for the organization unless it already has one with
+// the same name.
+//  NOTE: returns false on error
+func CreateIssueType(owner string, issueType IssueType) bool {
+    owner = OrgOwner(owner)
+    issueTypesMutex.Lock()
+    defer issueTypesMutex.Unlock()
+    types := orgIssueTypes(owner)
+    lower := strings.ToLower(issueType.Name)
+    if _, exists := types[lower]; exists {
+        return true
+    }
+    orgId := orgNodeIDs[owner]
+    if orgId == "" {
+        return false
+    }
+    created := GqlCreateIssueType(orgId, issueType)
+    if created.ID == "" {
+        return false
+    }
+    types[lower] = created
+    return true
+}
+
+// Set the issue type of an issue by the name of the organization issue type.
+//  NOTE: returns false on error or if there is no such enabled issue type.
+func SetIssueType(client *Client, owner, repo string, number int, name string) bool {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    issueType, found := GetIssueTypes(owner)[strings.ToLower(name)]
+    if !found || !issueType.IsEnabled {
+        return false
+    }
+    issue := getIssue(client.ptr, owner, repo, number)
+    if (issue == nil) || (issue.NodeID == nil) {
+        return false
+    }
+    return GqlUpdateIssueType(*issue.NodeID, issueType.ID)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Get the issue types of the organization, loading them on first use.
+//  NOTE: issueTypesMutex must be held.
+func orgIssueTypes(owner string) map[string]IssueType {
+    if types := issueTypes[owner]; types != nil {
+        return types
+    }
+    orgId, list := GqlIssueTypes(owner)
+    types := make(map[string]IssueType, len(list))
+    for _, issueType := range list {
+        types[strings.ToLower(issueType.Name)] = issueType
+    }
+    if orgId != "" {
+        orgNodeIDs[owner] = orgId
+        issueTypes[owner] = types
+    }
+    return types
+}
@@ -1,19 +1,72 @@
 // Github/label.go
 //
 // Functions supporting GitHub issue labels.
+//
+// @see https://docs.github.com/en/rest/issues/labels
 
 package Github
 
 import (
 	"fmt"
+	"strings"
+
+	"lib.virginia.edu/agita/log"
+	"lib.virginia.edu/agita/util"
 
 	"github.com/google/go-github/v69/github"
 )
 
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// The properties of a GitHub label.
+type Label struct {
+    Name        string
+    Color       string  // Six hex digits without a leading "#".
+    Description string
+}
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// GitHub limits on label properties.
+const (
+    LABEL_NAME_MAX        = 50
+    LABEL_DESCRIPTION_MAX = 100
+)
+
 // ============================================================================
 // Exported functions
 // ============================================================================
 
+// Get all labels of the repository by lowercase name.
+//  NOTE: label names are not case-sensitive on GitHub.
+//  NOTE: if an error was encountered a partial result may be returned
+func GetLabels(client *Client, owner, repo string) map[string]Label {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    list, _ := getLabels(client.ptr, owner, repo)
+    res  := make(map[string]Label, len(list))
+    for _, item := range list {
+        if item != nil {
+            label := Label{item.GetName(), item.GetColor(), item.GetDescription()}
+            res[strings.ToLower(label.Name)] = label
+        }
+    }
+    return res
+}
+
+// Create a label in the repository.
+//  NOTE: returns false on error
+func CreateLabel(client *Client, owner, repo string, label Label) bool {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    _, _, err := client.ptr.Issues.CreateLabel(ctx, owner, repo, label.request())
+    return log.ErrorValue(err) == nil
+}
+
 // Render a GitHub Label object as a string.
 func LabelString(label *github.Label) string {
     switch {
@@ -42,3 +95,40 @@ func LabelStrings(labels []*github.Label) []string {
     }
     return result
 }
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// Generate the GitHub object for creating the label.
+func (l Label) request() *github.Label {
+    res := &github.Label{Name: &l.Name}
+    if l.Color != "" {
+        res.Color = &l.Color
+    }
+    if l.Description != "" {
+        res.Description = &l.Description
+    }
+    return res
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Get all GitHub Label objects for the indicated repository.
+//  NOTE: if an error was encountered a partial list may be returned
+func getLabels(client *github.Client, owner, repo string) ([]*github.Label, error) {
+    res := []*github.Label{}
+    opt := &github.ListOptions{Page: 1, PerPage: MAX_PER_PAGE}
+    fn  := util.FuncName()
+    for opt.Page > 0 {
+        list, rsp, err := client.Issues.ListLabels(ctx, owner, repo, opt)
+        if err != nil {
+            return res, log.ErrorValueIn(fn, err)
+        }
+        res = append(res, list...)
+        opt.Page = rsp.NextPage
+    }
+    return res, nil
+}
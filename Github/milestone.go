@@ -0,0 +1,142 @@
+// Github/milestone.go
+//
+// Functions supporting GitHub repository milestones.
+//
+// @see https://docs.github.com/en/rest/issues/milestones
+
+package Github
+
+import (
+	"time"
+
+	"lib.virginia.edu/agita/log"
+	"lib.virginia.edu/agita/util"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// The properties of a GitHub milestone.
+type Milestone struct {
+    Number      int         // Assigned by GitHub on creation.
+    Title       string
+    Description string
+    DueOn       time.Time   // Zero if there is no due date.
+    Closed      bool
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Get all milestones of the repository, open or closed, by title.
+//  NOTE: if an error was encountered a partial result may be returned
+func GetMilestones(client *Client, owner, repo string) map[string]Milestone {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    list, _ := getMilestones(client.ptr, owner, repo)
+    res  := make(map[string]Milestone, len(list))
+    for _, item := range list {
+        if item != nil {
+            m := asMilestone(item)
+            res[m.Title] = m
+        }
+    }
+    return res
+}
+
+// Create a milestone in the repository.
+//  NOTE: returns the number of the new milestone or 0 on error
+func CreateMilestone(client *Client, owner, repo string, m Milestone) int {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    res, _, err := client.ptr.Issues.CreateMilestone(ctx, owner, repo, m.request())
+    if (log.ErrorValue(err) != nil) || (res == nil) {
+        return 0
+    }
+    return res.GetNumber()
+}
+
+// Update the properties of the milestone indicated by `m.Number`.
+//  NOTE: returns false on error
+func EditMilestone(client *Client, owner, repo string, m Milestone) bool {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    _, _, err := client.ptr.Issues.EditMilestone(ctx, owner, repo, m.Number, m.request())
+    return log.ErrorValue(err) == nil
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// Indicate whether the milestones have the same properties, ignoring numbers.
+//  NOTE: due dates are compared by day because GitHub does not keep the time
+func (m Milestone) Same(other Milestone) bool {
+    day := func(t time.Time) string {
+        if t.IsZero() { return "" }
+        return t.UTC().Format(time.DateOnly)
+    }
+    return (m.Title == other.Title) &&
+        (m.Description == other.Description) &&
+        (day(m.DueOn) == day(other.DueOn)) &&
+        (m.Closed == other.Closed)
+}
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// Generate the GitHub object for creating or editing the milestone.
+func (m Milestone) request() *github.Milestone {
+    state := "open"
+    if m.Closed {
+        state = "closed"
+    }
+    res := &github.Milestone{
+        Title:       &m.Title,
+        Description: &m.Description,
+        State:       &state,
+    }
+    if !m.DueOn.IsZero() {
+        res.DueOn = &github.Timestamp{Time: m.DueOn}
+    }
+    return res
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Translate a GitHub milestone object.
+func asMilestone(src *github.Milestone) Milestone {
+    return Milestone{
+        Number:      src.GetNumber(),
+        Title:       src.GetTitle(),
+        Description: src.GetDescription(),
+        DueOn:       src.GetDueOn().Time,
+        Closed:      src.GetState() == "closed",
+    }
+}
+
+// Get all GitHub Milestone objects for the indicated repository.
+//  NOTE: if an error was encountered a partial list may be returned
+func getMilestones(client *github.Client, owner, repo string) ([]*github.Milestone, error) {
+    res := []*github.Milestone{}
+    opt := &github.MilestoneListOptions{State: "all"}
+    opt.Page    = 1
+    opt.PerPage = MAX_PER_PAGE
+    fn  := util.FuncName()
+    for opt.Page > 0 {
+        list, rsp, err := client.Issues.ListMilestones(ctx, owner, repo, opt)
+        if err != nil {
+            return res, log.ErrorValueIn(fn, err)
+        }
+        res = append(res, list...)
+        opt.Page = rsp.NextPage
+    }
+    return res, nil
+}
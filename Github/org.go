@@ -35,6 +35,19 @@ func OrgOwner(owner string) string {
     return owner
 }
 
+// Get the logins of all members of the organization.
+//  NOTE: if `org` is blank it defaults to ORG
+//  NOTE: may return partial results on error
+func OrgMembers(client *Client, org string) []string {
+    if client == nil { client = MainClient() }
+    items, _ := getOrgMembers(client.ptr, org)
+    result := make([]string, 0, len(items))
+    for _, user := range items {
+        result = append(result, user.GetLogin())
+    }
+    return result
+}
+
 // ============================================================================
 // Internal functions
 // ============================================================================
@@ -65,7 +78,25 @@ func getOrgRepos(client *github.Client, org string) ([]*github.Repository, error
     org = OrgOwner(org)
     for opt.Page > 0 {
         list, rsp, err := client.Repositories.ListByOrg(ctx, org, opt)
-        extractRateLimit(rsp)
+        if err != nil {
+            return res, log.ErrorValueIn(fn, err)
+        }
+        res = append(res, list...)
+        opt.Page = rsp.NextPage
+    }
+    return res, nil
+}
+
+// Get all GitHub User objects for members of org.
+//  NOTE: the objects only have basic properties like Login.
+//  NOTE: if `org` is blank it defaults to ORG
+func getOrgMembers(client *github.Client, org string) ([]*github.User, error) {
+    res := []*github.User{}
+    opt := &github.ListMembersOptions{ListOptions: *listOptions()}
+    fn  := util.FuncName()
+    org = OrgOwner(org)
+    for opt.Page > 0 {
+        list, rsp, err := client.Organizations.ListMembers(ctx, org, opt)
         if err != nil {
             return res, log.ErrorValueIn(fn, err)
         }
@@ -0,0 +1,224 @@
+// Github/project_v2.go
+//
+// Organization projects (Projects v2), which are managed through the GraphQL
+// API.
+//
+// @see https://docs.github.com/en/issues/planning-and-tracking-with-projects
+
+package Github
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A GitHub organization project.
+type ProjectV2 struct {
+    ID      string  // Unique node ID assigned by GitHub.
+    Number  int
+    Title   string
+    URL     string
+}
+
+// A field of a project.
+type ProjectField struct {
+    ID      string              // Unique node ID assigned by GitHub.
+    Name    string
+    Options map[string]string   // Option (or iteration) node IDs by name.
+}
+
+// An item of a project.
+type ProjectItem struct {
+    ID      string              // Unique node ID assigned by GitHub.
+    Repo    string              // Repository of the issue (blank for a draft).
+    Number  int                 // Issue number.
+    Values  map[string]string   // Field values (or option or iteration names) by field name.
+}
+
+// An iteration of a project iteration field.
+type Iteration struct {
+    Title     string
+    StartDate time.Time
+    Duration  int       // Length of the iteration in days.
+}
+
+// An option of a project single-select field.
+type FieldOption struct {
+    Name        string
+    Color       string  // E.g. "GRAY", "BLUE", "GREEN", "YELLOW", "RED".
+    Description string
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Get the organization project with the given title.
+//  NOTE: returns nil if there is no such project or on error.
+func GetProjectV2(owner, title string) *ProjectV2 {
+    owner = OrgOwner(owner)
+    _, projects := GqlProjectsV2(owner, title)
+    for _, project := range projects {
+        if project.Title == title {
+            return &project
+        }
+    }
+    return nil
+}
+
+// Create an organization project linked to the repository.
+//  NOTE: returns nil on error.
+func CreateProjectV2(owner, repo, title string) *ProjectV2 {
+    owner = OrgOwner(owner)
+    orgId, _ := GqlProjectsV2(owner, title)
+    if orgId == "" {
+        return nil
+    }
+    repoId  := GqlRepositoryID(owner, repo)
+    project := GqlCreateProjectV2(orgId, repoId, title)
+    if project.ID == "" {
+        return nil
+    }
+    return &project
+}
+
+// Get the fields of the project by name.
+//  NOTE: returns an empty result on error.
+func GetProjectFields(project *ProjectV2) map[string]ProjectField {
+    res := map[string]ProjectField{}
+    for _, field := range GqlProjectV2Fields(project.ID) {
+        res[field.Name] = field
+    }
+    return res
+}
+
+// Replace the options of a single-select field of a project.
+//  NOTE: values already set for the field on project items are lost.
+func SetFieldOptions(field ProjectField, options []FieldOption) bool {
+    input := make([]SingleSelectOptionInput, 0, len(options))
+    for _, opt := range options {
+        input = append(input, SingleSelectOptionInput(opt))
+    }
+    return GqlSetFieldOptions(field.ID, input)
+}
+
+// Create an iteration field for a project with the given iterations.
+//  NOTE: the iterations must be in chronological order.
+func CreateIterationField(project *ProjectV2, name string, iterations []Iteration) bool {
+    if len(iterations) == 0 {
+        return false
+    }
+    return GqlCreateIterationField(project.ID, name, iterationConfig(iterations))
+}
+
+// Replace the iterations of an iteration field of a project.
+//  NOTE: the iterations must be in chronological order.
+//  NOTE: values already set for the field on project items may be lost.
+func SetFieldIterations(field ProjectField, iterations []Iteration) bool {
+    if len(iterations) == 0 {
+        return false
+    }
+    return GqlSetFieldIterations(field.ID, iterationConfig(iterations))
+}
+
+// Create a text, number or date field for a project, where `dataType` is
+// "text", "number" or "date" (default "text").
+func CreateProjectField(project *ProjectV2, name, dataType string) bool {
+    switch dataType = strings.ToUpper(dataType); dataType {
+        case "NUMBER", "DATE": // as given
+        default:               dataType = "TEXT"
+    }
+    return GqlCreateProjectV2Field(project.ID, name, dataType)
+}
+
+// Get the items of a project in project order.
+//  NOTE: returns nil on error.
+func GetProjectItems(project *ProjectV2) []ProjectItem {
+    return GqlProjectV2Items(project.ID)
+}
+
+// Add an issue to the bottom of a project.
+//  NOTE: returns the node ID of the project item or blank on error.
+func AddProjectItem(client *Client, project *ProjectV2, owner, repo string, number int) string {
+    if client == nil { client = MainClient() }
+    owner = OrgOwner(owner)
+    issue := getIssue(client.ptr, owner, repo, number)
+    if (issue == nil) || (issue.NodeID == nil) {
+        return ""
+    }
+    return GqlAddProjectV2Item(project.ID, *issue.NodeID)
+}
+
+// Place a project item after another project item (or at the top if `after`
+// is blank).
+func MoveProjectItem(project *ProjectV2, item, after string) bool {
+    return GqlMoveProjectV2Item(project.ID, item, after)
+}
+
+// Set a single-select or iteration field of a project item by the name of
+// the option or iteration.
+//  NOTE: returns false if there is no such option.
+func SetProjectItemField(project *ProjectV2, item string, field ProjectField, name string, iteration bool) bool {
+    id := field.Options[name]
+    if id == "" {
+        return false
+    }
+    value := githubv4.ProjectV2FieldValue{}
+    if iteration {
+        value.IterationID = githubv4.NewString(githubv4.String(id))
+    } else {
+        value.SingleSelectOptionID = githubv4.NewString(githubv4.String(id))
+    }
+    return GqlSetProjectV2ItemField(project.ID, item, field.ID, value)
+}
+
+// Set a text, number or date field of a project item, where `dataType` is
+// "text", "number" or "date" (default "text").
+//  NOTE: returns false if the value is not valid for the field type.
+func SetProjectItemValue(project *ProjectV2, item string, field ProjectField, dataType, value string) bool {
+    result := githubv4.ProjectV2FieldValue{}
+    switch strings.ToLower(dataType) {
+        case "number":
+            number, err := strconv.ParseFloat(value, 64)
+            if err != nil {
+                return false
+            }
+            result.Number = githubv4.NewFloat(githubv4.Float(number))
+        case "date":
+            date, err := time.Parse(time.DateOnly, value[:min(len(value), len(time.DateOnly))])
+            if err != nil {
+                return false
+            }
+            result.Date = githubv4.NewDate(githubv4.Date{Time: date})
+        default:
+            result.Text = githubv4.NewString(githubv4.String(value))
+    }
+    return GqlSetProjectV2ItemField(project.ID, item, field.ID, result)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The configuration of an iteration field with the given iterations.
+func iterationConfig(iterations []Iteration) IterationConfigurationInput {
+    config := IterationConfigurationInput{
+        StartDate: iterations[0].StartDate.Format(time.DateOnly),
+        Duration:  iterations[0].Duration,
+    }
+    for _, iter := range iterations {
+        config.Iterations = append(config.Iterations, IterationInput{
+            Title:     iter.Title,
+            StartDate: iter.StartDate.Format(time.DateOnly),
+            Duration:  iter.Duration,
+        })
+    }
+    return config
+}
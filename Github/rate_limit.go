@@ -3,35 +3,36 @@
 // Functions supporting GitHub rate limit processing.
 //
 // Note that GitHub has a rate limit on API requests per hour and also a
-// secondary rate limits which apply to content-creating requests.
+// secondary rate limits which apply to content-creating requests.  Both are
+// accounted for by limiter.Github, through which all GitHub requests are made.
 //
 // @see https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api
 
 package Github
 
 import (
+	"lib.virginia.edu/agita/limiter"
 	"lib.virginia.edu/agita/log"
 
 	"github.com/google/go-github/v69/github"
 )
 
-// ============================================================================
-// Exported variables
-// ============================================================================
-
-// The last acquired core rate limit status.
-var LastRate github.Rate
-
 // ============================================================================
 // Exported functions
 // ============================================================================
 
-// Get the last rate limit status.
+// Get the last reported core rate limit status.
 func RateLimit() github.Rate {
-    if LastRate.Limit == 0 {
-        GetRateLimit(nil)
+    budget := limiter.Github.Budget(limiter.CORE)
+    if budget.Limit == 0 {
+        return *GetRateLimit(nil).Core
+    }
+    return github.Rate{
+        Limit:     budget.Limit,
+        Remaining: budget.Remaining,
+        Used:      budget.Used,
+        Reset:     github.Timestamp{Time: budget.Reset},
     }
-    return LastRate
 }
 
 // Get the current rate limit status.
@@ -41,23 +42,8 @@ func GetRateLimit(client *Client) *github.RateLimits {
     if client == nil { client = MainClient() }
     result, _, err := client.ptr.RateLimit.Get(ctx)
     log.ErrorValue(err)
-    if (result != nil) && (result.Core != nil) {
-        LastRate = *result.Core
-        return result
-    } else {
-        return &github.RateLimits{Core: &LastRate}
-    }
-}
-
-// ============================================================================
-// Internal functions
-// ============================================================================
-
-// Get the current rate limit status from a GitHub API response.
-//  NOTE: every GitHub API request should be followed by this function.
-func extractRateLimit(response *github.Response) {
-    if response != nil {
-        LastRate = response.Rate
+    if (result == nil) || (result.Core == nil) {
+        result = &github.RateLimits{Core: &github.Rate{}}
     }
+    return result
 }
-
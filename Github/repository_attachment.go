@@ -0,0 +1,365 @@
+// Github/repository_attachment.go
+//
+// Attachment files stored in the ATTACH_DIR of issues-only repositories
+// through the Git Data API.
+//
+// File content is streamed from local files so that large attachments are
+// never held in memory.
+
+package Github
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"lib.virginia.edu/agita/log"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A file to be stored in ATTACH_DIR, whose content is either a local file or
+// given directly.
+type ProjFile struct {
+    Name    string  // File name within ATTACH_DIR.
+    Path    string  // Local file holding the content.
+    Content string  // Content if Path is blank.
+}
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The largest file which GitHub will store in a repository.
+const MAX_FILE_SIZE = 100 * 1024 * 1024
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Add attachment files to an existing issues-only repository in a single
+// commit.
+//  NOTE: returns *FileTooLargeError if any file exceeds MAX_FILE_SIZE, in which
+//  case no files are stored.
+func CreateProjAttachments(client *Client, name, message string, files []ProjFile) error {
+    if client == nil { client = MainClient() }
+    for _, file := range files {
+        size, err := file.Size()
+        if err != nil {
+            return err
+        }
+        if err := CheckFileSize(name, file.Name, size); err != nil {
+            return err
+        }
+    }
+    return createProjAttachments(client, name, message, files)
+}
+
+// Get the content of a file in the attachments folder of an existing
+// issues-only repository.
+//  NOTE: returns false if there is no such file.
+func GetProjAttachment(client *Client, name, file string) (string, bool) {
+    if client == nil { client = MainClient() }
+    return getProjAttachment(client, name, file)
+}
+
+// Check the files in the attachments folder of an existing issues-only
+// repository against their expected contents.
+//  NOTE: returns the names of the files whose size or checksum differs.
+func VerifyProjAttachments(client *Client, name string, files []ProjFile) []string {
+    if client == nil { client = MainClient() }
+    return verifyProjAttachments(client, name, files)
+}
+
+// Get the repository shared by all projects for storing attachments, creating
+// it with the given visibility if necessary.
+func GetAttachRepo(client *Client, name string, private bool) (result *Repository) {
+    if client == nil { client = MainClient() }
+    if result = GetRepository(client, ORG, name, true); result == nil {
+        result = createAttachRepository(client, name, private)
+    }
+    return
+}
+
+// Indicate whether a file of the given size can be stored in a repository.
+//  NOTE: returns *FileTooLargeError if the file exceeds MAX_FILE_SIZE.
+func CheckFileSize(repo, file string, size int64) error {
+    if size > MAX_FILE_SIZE {
+        return &FileTooLargeError{Repo: repo, File: file, Size: size, Limit: MAX_FILE_SIZE}
+    }
+    return nil
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// The size of the file content.
+func (f ProjFile) Size() (int64, error) {
+    if f.Path == "" {
+        return int64(len(f.Content)), nil
+    }
+    info, err := os.Stat(f.Path)
+    if err != nil {
+        return 0, err
+    }
+    return info.Size(), nil
+}
+
+// Open the file content.
+//  NOTE: the caller must close the result.
+func (f ProjFile) Open() (io.ReadCloser, error) {
+    if f.Path == "" {
+        return io.NopCloser(strings.NewReader(f.Content)), nil
+    }
+    return os.Open(f.Path)
+}
+
+// ============================================================================
+// Internal constants
+// ============================================================================
+
+// The branch of a project repository where attachments are stored.
+const projBranch = "main"
+
+// Text files up to this size are given directly in the tree request rather
+// than uploaded as separate blobs.
+const inlineTextMax = 1024 * 1024
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Create a repository for storing attachments with an initial commit on
+// projBranch to which attachment commits can be added.
+func createAttachRepository(client *Client, name string, private bool) *Repository {
+    data := github.Repository{
+        Owner:          getUser(client.ptr, ORG),
+        Name:           github.Ptr(name),
+        Description:    github.Ptr("Attachments of Jira issues transferred to GitHub"),
+        Private:        github.Ptr(private),
+        AutoInit:       github.Ptr(true),
+    }
+    return CreateRepository(client, &RepositoryRequest{Repository: data})
+}
+
+// Add attachment files to an existing issues-only repository by uploading
+// each as a blob and committing them together through the Git Data API, which
+// avoids the separate commit made by each Repositories.CreateFile request.
+func createProjAttachments(client *Client, name, message string, files []ProjFile) error {
+    if len(files) == 0 {
+        return nil
+    }
+    srv := client.ptr.Git
+    ref, _, err := srv.GetRef(ctx, ORG, name, "heads/"+projBranch)
+    if err != nil {
+        return err
+    }
+    parent, _, err := srv.GetCommit(ctx, ORG, name, ref.GetObject().GetSHA())
+    if err != nil {
+        return err
+    }
+
+    // Upload the files as blobs; small text files are given in the tree.
+    entries := make([]*github.TreeEntry, 0, len(files))
+    for _, file := range files {
+        entry := &github.TreeEntry{
+            Path: github.Ptr(ATTACH_DIR + "/" + file.Name),
+            Mode: github.Ptr("100644"),
+            Type: github.Ptr("blob"),
+        }
+        if text, ok := inlineText(file); ok {
+            entry.Content = github.Ptr(text)
+        } else if sha, err := createBlob(client, name, file); err != nil {
+            return err
+        } else {
+            entry.SHA = github.Ptr(sha)
+        }
+        entries = append(entries, entry)
+    }
+
+    // Commit the blobs on top of the current tree and advance the branch.
+    tree, _, err := srv.CreateTree(ctx, ORG, name, parent.GetTree().GetSHA(), entries)
+    if err != nil {
+        return err
+    }
+    commit := &github.Commit{
+        Message: github.Ptr(message),
+        Tree:    tree,
+        Parents: []*github.Commit{{SHA: parent.SHA}},
+    }
+    commit, _, err = srv.CreateCommit(ctx, ORG, name, commit, nil)
+    if err != nil {
+        return err
+    }
+    ref.Object.SHA = commit.SHA
+    _, _, err = srv.UpdateRef(ctx, ORG, name, ref, false)
+    return err
+}
+
+// Upload a file as a blob, streaming its content as base64 in the request.
+//  NOTE: returns the SHA of the blob.
+//  NOTE: the request body can be replayed if the request must be retried.
+func createBlob(client *Client, name string, file ProjFile) (string, error) {
+    size, err := file.Size()
+    if err != nil {
+        return "", err
+    }
+    const prefix = `{"encoding":"base64","content":"`
+    const suffix = `"}`
+    body := func() (io.ReadCloser, error) {
+        src, err := file.Open()
+        if err != nil {
+            return nil, err
+        }
+        reader, writer := io.Pipe()
+        go func() {
+            defer src.Close()
+            _, err := io.WriteString(writer, prefix)
+            if err == nil {
+                encoder := base64.NewEncoder(base64.StdEncoding, writer)
+                if _, err = io.Copy(encoder, src); err == nil {
+                    err = encoder.Close()
+                }
+            }
+            if err == nil {
+                _, err = io.WriteString(writer, suffix)
+            }
+            writer.CloseWithError(err)
+        }()
+        return reader, nil
+    }
+    urlStr   := fmt.Sprintf("repos/%s/%s/git/blobs", ORG, name)
+    req, err := client.ptr.NewRequest(http.MethodPost, urlStr, nil)
+    if err != nil {
+        return "", err
+    }
+    if req.Body, err = body(); err != nil {
+        return "", err
+    }
+    req.GetBody       = body
+    req.ContentLength = int64(len(prefix) + base64.StdEncoding.EncodedLen(int(size)) + len(suffix))
+    req.Header.Set("Content-Type", "application/json")
+    blob := &github.Blob{}
+    if _, err = client.ptr.Do(ctx, req, blob); err != nil {
+        return "", err
+    }
+    return blob.GetSHA(), nil
+}
+
+// The content of a small text file.
+//  NOTE: returns false if the file is large or is not text.
+func inlineText(file ProjFile) (string, bool) {
+    if size, err := file.Size(); (err != nil) || (size > inlineTextMax) {
+        return "", false
+    }
+    src, err := file.Open()
+    if err != nil {
+        return "", false
+    }
+    defer src.Close()
+    bytes, err := io.ReadAll(src)
+    if err != nil {
+        return "", false
+    }
+    text := string(bytes)
+    return text, utf8.ValidString(text) && !strings.ContainsRune(text, 0)
+}
+
+// Get the content of a file in the attachments folder of an existing
+// issues-only repository.
+//  NOTE: Repositories.DownloadContents is used because Repositories.GetContents
+//  does not return the content of files larger than 1 MB.
+func getProjAttachment(client *Client, name, file string) (string, bool) {
+    path := ATTACH_DIR + "/" + file
+    body, rsp, err := client.ptr.Repositories.DownloadContents(ctx, ORG, name, path, nil)
+    switch {
+        case (rsp != nil) && (rsp.StatusCode == http.StatusNotFound):
+            return "", false
+        case (err != nil) && strings.HasPrefix(err.Error(), "no file named"):
+            return "", false
+        case log.ErrorValue(err) != nil:
+            return "", false
+    }
+    defer body.Close()
+    bytes, err := io.ReadAll(body)
+    if log.ErrorValue(err) != nil {
+        return "", false
+    }
+    return string(bytes), true
+}
+
+// Check the files in the attachments folder of an existing issues-only
+// repository against their expected contents by comparing the size and Git
+// blob hash of each.
+func verifyProjAttachments(client *Client, name string, files []ProjFile) []string {
+    srv    := client.ptr.Git
+    failed := make([]string, 0, len(files))
+    for _, file := range files {
+        failed = append(failed, file.Name)
+    }
+    root, _, err := srv.GetTree(ctx, ORG, name, projBranch, false)
+    if log.ErrorValue(err) != nil {
+        return failed
+    }
+    dir := ""
+    for _, entry := range root.Entries {
+        if entry.GetPath() == ATTACH_DIR {
+            dir = entry.GetSHA()
+        }
+    }
+    if dir == "" {
+        log.Error("%s: no %s folder", name, ATTACH_DIR)
+        return failed
+    }
+    tree, _, err := srv.GetTree(ctx, ORG, name, dir, false)
+    if log.ErrorValue(err) != nil {
+        return failed
+    }
+    stored := map[string]*github.TreeEntry{}
+    for _, entry := range tree.Entries {
+        stored[entry.GetPath()] = entry
+    }
+    for _, file := range files {
+        entry, found := stored[file.Name]
+        if !found {
+            continue
+        }
+        size, hash, err := gitBlobHash(file)
+        if (err == nil) && (int64(entry.GetSize()) == size) && (entry.GetSHA() == hash) {
+            failed = slices.DeleteFunc(failed, func(name string) bool { return name == file.Name })
+        }
+    }
+    return failed
+}
+
+// The size of the file and the hash which Git gives to a blob with its
+// content.
+func gitBlobHash(file ProjFile) (int64, string, error) {
+    size, err := file.Size()
+    if err != nil {
+        return 0, "", err
+    }
+    src, err := file.Open()
+    if err != nil {
+        return 0, "", err
+    }
+    defer src.Close()
+    hash := sha1.New()
+    fmt.Fprintf(hash, "blob %d\x00", size)
+    if _, err = io.Copy(hash, src); err != nil {
+        return 0, "", err
+    }
+    return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,101 @@
+// Github/repository_attachment_test.go
+
+package Github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestGitBlobHash(t *testing.T) {
+    const fn = "gitBlobHash"
+
+	type testCase struct {
+		name string
+		file ProjFile
+		size int64
+		want string
+		err  bool
+	}
+
+    // Hashes given by "git hash-object" for the same content.
+    const (
+        emptyHash = "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"
+        helloHash = "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"
+        nulHash   = "20b5be91886d0b6f26dc98a225c0dac05fe2c86e"
+    )
+    hello := "hello world\n"
+    dir   := t.TempDir()
+    path  := filepath.Join(dir, "hello.txt")
+    if err := os.WriteFile(path, []byte(hello), 0644); err != nil {
+        t.Fatal(err)
+    }
+    Case := func(idx int, file ProjFile, size int64, want string, err bool) testCase {
+        return testCase{test.CaseName(fn, idx), file, size, want, err}
+    }
+
+	tests := []testCase{
+        Case(0, ProjFile{Content: ""},      0,  emptyHash, false),
+        Case(1, ProjFile{Content: hello},   12, helloHash, false),
+        Case(2, ProjFile{Content: "a\x00b"}, 3, nulHash,   false),
+        Case(3, ProjFile{Path: path},       12, helloHash, false),
+        Case(4, ProjFile{Path: filepath.Join(dir, "missing")}, 0, "", true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            size, hash, err := gitBlobHash(tt.file)
+            switch {
+                case tt.err && (err == nil):
+                    t.Errorf("%s() error = nil, want error", fn)
+                case !tt.err && (err != nil):
+                    t.Errorf("%s() error = %v", fn, err)
+                case (size != tt.size) || (hash != tt.want):
+                    t.Errorf("%s() = (%d, %q), want (%d, %q)", fn, size, hash, tt.size, tt.want)
+            }
+		})
+	}
+}
+
+func TestInlineText(t *testing.T) {
+    const fn = "inlineText"
+
+	type testCase struct {
+		name    string
+		content string
+		want    bool
+	}
+
+    Case := func(idx int, content string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), content, want}
+    }
+
+	tests := []testCase{
+        Case(0, "",                                        true),
+        Case(1, "plain text\nwith lines\n",                true),
+        Case(2, "UTF-8 text: café ✓",                      true),
+        Case(3, "a\x00b",                                  false),
+        Case(4, "\x89PNG\r\n\x1a\n",                       false),
+        Case(5, "latin-1 \xe9",                            false),
+        Case(6, strings.Repeat("x", inlineTextMax),        true),
+        Case(7, strings.Repeat("x", inlineTextMax + 1),    false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            text, got := inlineText(ProjFile{Content: tt.content})
+            if got != tt.want {
+                t.Errorf("%s() = %v, want %v", fn, got, tt.want)
+            }
+            if got && (text != tt.content) {
+                t.Errorf("%s() text = %q, want %q", fn, text, tt.content)
+            }
+		})
+	}
+}
@@ -168,8 +168,7 @@ func createFakeRepository(client *Client) *Repository {
 // Get all temporary test repositories from GitHub.
 func getTemporaryRepos(client *Client) []*Repository {
     result := []*Repository{}
-    res, rsp, err := client.ptr.Search.Repositories(ctx, FAKE_REPO_NAME, nil)
-    extractRateLimit(rsp)
+    res, _, err := client.ptr.Search.Repositories(ctx, FAKE_REPO_NAME, nil)
     if log.ErrorValue(err) == nil {
         for _, repo := range res.Repositories {
             result = append(result, AsRepositoryType(client, repo))
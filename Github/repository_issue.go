@@ -42,7 +42,6 @@ func getRepoIssues(client *github.Client, owner, repo string) ([]*github.Issue,
     fn  := util.FuncName()
     for opt.Page > 0 {
         list, rsp, err := client.Issues.ListByRepo(ctx, owner, repo, opt)
-        extractRateLimit(rsp)
         if err != nil {
             return res, log.ErrorValueIn(fn, err)
         }
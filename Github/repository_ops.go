@@ -47,8 +47,7 @@ func getRepository(client *github.Client, owner, name string, silent bool) *gith
         return nil
     }
     owner = OrgOwner(owner)
-    repo, rsp, err := client.Repositories.Get(ctx, owner, name)
-    extractRateLimit(rsp)
+    repo, _, err := client.Repositories.Get(ctx, owner, name)
     if (err != nil) || !validateRepo(repo, owner, name) {
         if !silent { log.ErrorValue(err) }
         return nil
@@ -81,8 +80,7 @@ func validateRepo(repo *github.Repository, owner, name string) bool {
 // Set repository topics.
 func setRepositoryTopics(client *github.Client, owner, name string, topics ...string) {
     org := OrgOwner(owner)
-    _, rsp, err := client.Repositories.ReplaceAllTopics(ctx, org, name, topics)
-    extractRateLimit(rsp)
+    _, _, err := client.Repositories.ReplaceAllTopics(ctx, org, name, topics)
     log.ErrorValue(err)
 }
 
@@ -114,8 +112,7 @@ func createRepository(client *github.Client, data *RepositoryRequest) *github.Re
     } else {
         owner = *ptr.Login
     }
-    repo, rsp, err := client.Repositories.Create(ctx, owner, &data.Repository)
-    extractRateLimit(rsp)
+    repo, _, err := client.Repositories.Create(ctx, owner, &data.Repository)
     if (log.ErrorValue(err) != nil) || !validateRepo(repo, owner, name) {
         return nil
     }
@@ -130,8 +127,7 @@ func deleteRepository(client *github.Client, owner, name string) {
         panic(ERR_NO_REPO_GIVEN)
     }
     owner = OrgOwner(owner)
-    rsp, err := client.Repositories.Delete(ctx, owner, name)
-    extractRateLimit(rsp)
+    _, err := client.Repositories.Delete(ctx, owner, name)
     log.ErrorValue(err)
 }
 
@@ -52,8 +52,9 @@ func CreateProjRepo(client *Client, name string) *Repository {
 }
 
 // Add a copy of an attachment to an existing issues-only repository.
-func CreateProjAttachment(client *Client, name, file, content string) {
-    createProjAttachment(client, name, file, content)
+//  NOTE: returns false if the file could not be stored.
+func CreateProjAttachment(client *Client, name, file, content string) bool {
+    return createProjAttachment(client, name, file, content)
 }
 
 // ============================================================================
@@ -103,11 +104,9 @@ func createProjRepository(client *Client, name string) *Repository {
         file := "README.md"
         srv  := client.ptr.Repositories
         var current *github.RepositoryContent
-        var rsp *github.Response
         var err error
         for range maxGetReadmeRetries {
-            current, _, rsp, err = srv.GetContents(ctx, owner, name, file, nil)
-            extractRateLimit(rsp)
+            current, _, _, err = srv.GetContents(ctx, owner, name, file, nil)
             if err != nil {
                 if strings.Contains(err.Error(), "This repository is empty") {
                     time.Sleep(time.Second)
@@ -126,8 +125,7 @@ func createProjRepository(client *Client, name string) *Repository {
                 Content: []byte(generateReadmeContent(data)),
                 SHA:     current.SHA,
             }
-            _, rsp, err := srv.UpdateFile(ctx, owner, name, file, opts)
-            extractRateLimit(rsp)
+            _, _, err := srv.UpdateFile(ctx, owner, name, file, opts)
             log.ErrorValue(err)
         }
 
@@ -153,6 +151,10 @@ At the top of each comment body, lines with "` + COMMENT_ANNOTATION_TAG +
 
 Each attachment file name is the original Jira attachment file name prefixed
 with the Jira issue key with which it is associated.
+A file attached to several Jira issues is stored only once, under the name
+given by the first of them; MANIFEST.json lists the original Jira attachments
+(ID, author, creation time and MIME type) and the issues which refer to each
+file.
 Note that while some of these are referenced directly by any issue or comment
 body (e.g. as embedded images), not all of them are.
 `
@@ -170,15 +172,14 @@ func generateReadmeContent(data *github.TemplateRepoRequest) string {
 //  NOTE: There is a to-be-determined maximum attachment size; may fail with:
 //  "Sorry, the file is too large to be processed. Consider creating/updating
 //  the file in a local clone and pushing it to GitHub."
-func createProjAttachment(client *Client, name, file, content string) {
+func createProjAttachment(client *Client, name, file, content string) bool {
     opts := &github.RepositoryContentFileOptions{
         Message: github.Ptr("Stored attachment " + file),
         Content: []byte(content),
     }
     file = ATTACH_DIR + "/" + file
-    _, rsp, err := client.ptr.Repositories.CreateFile(ctx, ORG, name, file, opts)
-    extractRateLimit(rsp)
-    log.ErrorValue(err)
+    _, _, err := client.ptr.Repositories.CreateFile(ctx, ORG, name, file, opts)
+    return log.ErrorValue(err) == nil
 }
 
 // ============================================================================
@@ -0,0 +1,261 @@
+// Github/repository_release.go
+//
+// Attachment files stored as the assets of a release of an issues-only
+// repository, which allows much larger files than the repository itself.
+//
+// File content is streamed from local files so that large attachments are
+// never held in memory.
+
+package Github
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"lib.virginia.edu/agita/log"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The largest file which GitHub will store as a release asset.
+const MAX_ASSET_SIZE = 2 * 1024 * 1024 * 1024
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Add attachment files as assets of the release of an existing issues-only
+// repository with the given tag, creating the release if necessary.
+//  NOTE: an existing asset with the same name is replaced.
+//  NOTE: returns *FileTooLargeError if any file exceeds MAX_ASSET_SIZE, in
+//  which case no files are stored.
+func CreateReleaseAssets(client *Client, name, tag string, files []ProjFile) error {
+    if client == nil { client = MainClient() }
+    for _, file := range files {
+        size, err := file.Size()
+        if err != nil {
+            return err
+        }
+        if size > MAX_ASSET_SIZE {
+            return &FileTooLargeError{Repo: name, File: file.Name, Size: size, Limit: MAX_ASSET_SIZE}
+        }
+    }
+    return createReleaseAssets(client, name, tag, files)
+}
+
+// Check the assets of the release of an existing issues-only repository with
+// the given tag against the files which were stored.
+//  NOTE: returns the names of the files whose asset is missing or whose size
+//  or SHA-256 checksum differs.
+//  NOTE: each asset is downloaded to compute its checksum.
+func VerifyReleaseAssets(client *Client, name, tag string, files []ProjFile) []string {
+    if client == nil { client = MainClient() }
+    return verifyReleaseAssets(client, name, tag, files)
+}
+
+// The name of the release asset for an attachment file.
+//  NOTE: GitHub replaces unsupported characters in asset names with "." so
+//  that is done here to allow the asset URL to be known in advance.  Because
+//  different file names could then give the same asset name (e.g. "a b.png"
+//  and "a(b.png"), a name which had to be changed is made unique by adding
+//  part of a hash of the original name before its extension.
+func ReleaseAssetName(file string) string {
+    name := assetNameChars.ReplaceAllString(file, ".")
+    if name == file {
+        return name
+    }
+    sum := sha1.Sum([]byte(file))
+    ext := path.Ext(name)
+    return strings.TrimSuffix(name, ext) + "-" + hex.EncodeToString(sum[:4]) + ext
+}
+
+// The path of a release asset relative to the repository URL.
+func ReleaseAssetPath(tag, file string) string {
+    return "releases/download/" + url.PathEscape(tag) + "/" + url.PathEscape(ReleaseAssetName(file))
+}
+
+// ============================================================================
+// Internal variables
+// ============================================================================
+
+// Characters which GitHub does not allow in release asset names.
+var assetNameChars = regexp.MustCompile(`[^A-Za-z0-9._+-]`)
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Upload attachment files as assets of the release with the given tag,
+// replacing any existing assets with the same names.
+//  NOTE: returns an error without uploading if two files would have the same
+//  asset name.
+func createReleaseAssets(client *Client, name, tag string, files []ProjFile) error {
+    if len(files) == 0 {
+        return nil
+    }
+    names := map[string]string{}
+    for _, file := range files {
+        asset := ReleaseAssetName(file.Name)
+        if other, found := names[asset]; found && (other != file.Name) {
+            return fmt.Errorf("%q and %q have the same asset name %q", other, file.Name, asset)
+        }
+        names[asset] = file.Name
+    }
+    release, err := getOrCreateRelease(client, name, tag)
+    if err != nil {
+        return err
+    }
+    assets, err := listReleaseAssets(client, name, release.GetID())
+    if err != nil {
+        return err
+    }
+    for _, file := range files {
+        asset := ReleaseAssetName(file.Name)
+        if old, found := assets[asset]; found {
+            _, err := client.ptr.Repositories.DeleteReleaseAsset(ctx, ORG, name, old.GetID())
+            if err != nil {
+                return err
+            }
+        }
+        if err := uploadReleaseAsset(client, name, release.GetID(), file); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Upload a file as a release asset, streaming its content in the request.
+//  NOTE: the request body can be replayed if the request must be retried.
+func uploadReleaseAsset(client *Client, name string, release int64, file ProjFile) error {
+    size, err := file.Size()
+    if err != nil {
+        return err
+    }
+    asset    := ReleaseAssetName(file.Name)
+    kind     := mime.TypeByExtension(path.Ext(asset))
+    urlStr   := fmt.Sprintf("repos/%s/%s/releases/%d/assets?name=%s", ORG, name, release, url.QueryEscape(asset))
+    body, err := file.Open()
+    if err != nil {
+        return err
+    }
+    req, err := client.ptr.NewUploadRequest(urlStr, body, size, kind)
+    if err != nil {
+        body.Close()
+        return err
+    }
+    req.GetBody = func() (io.ReadCloser, error) { return file.Open() }
+    _, err = client.ptr.Do(ctx, req, &github.ReleaseAsset{})
+    return err
+}
+
+// Get the release with the given tag, creating it if necessary.
+func getOrCreateRelease(client *Client, name, tag string) (*github.RepositoryRelease, error) {
+    srv := client.ptr.Repositories
+    release, rsp, err := srv.GetReleaseByTag(ctx, ORG, name, tag)
+    if (rsp != nil) && (rsp.StatusCode == http.StatusNotFound) {
+        data := &github.RepositoryRelease{
+            TagName:         github.Ptr(tag),
+            TargetCommitish: github.Ptr(projBranch),
+            Name:            github.Ptr("Jira attachments"),
+            Body:            github.Ptr("Jira attachments too large to be stored in " + ATTACH_DIR + "."),
+        }
+        release, _, err = srv.CreateRelease(ctx, ORG, name, data)
+    }
+    return release, err
+}
+
+// Get the assets of a release by name.
+func listReleaseAssets(client *Client, name string, release int64) (map[string]*github.ReleaseAsset, error) {
+    result := map[string]*github.ReleaseAsset{}
+    opts   := &github.ListOptions{PerPage: 100}
+    for {
+        page, rsp, err := client.ptr.Repositories.ListReleaseAssets(ctx, ORG, name, release, opts)
+        if err != nil {
+            return result, err
+        }
+        for _, asset := range page {
+            result[asset.GetName()] = asset
+        }
+        if rsp.NextPage == 0 {
+            break
+        }
+        opts.Page = rsp.NextPage
+    }
+    return result, nil
+}
+
+// Check the assets of the release with the given tag by comparing the size and
+// SHA-256 checksum of each with that of its file.
+func verifyReleaseAssets(client *Client, name, tag string, files []ProjFile) []string {
+    failed := make([]string, 0, len(files))
+    for _, file := range files {
+        failed = append(failed, file.Name)
+    }
+    release, _, err := client.ptr.Repositories.GetReleaseByTag(ctx, ORG, name, tag)
+    if log.ErrorValue(err) != nil {
+        return failed
+    }
+    assets, err := listReleaseAssets(client, name, release.GetID())
+    if log.ErrorValue(err) != nil {
+        return failed
+    }
+    failed = failed[:0]
+    for _, file := range files {
+        size, err := file.Size()
+        asset, ok := assets[ReleaseAssetName(file.Name)]
+        switch {
+            case (err != nil) || !ok || (int64(asset.GetSize()) != size) || (asset.GetState() != "uploaded"):
+                failed = append(failed, file.Name)
+            case !sameAssetContent(client, name, asset.GetID(), file):
+                failed = append(failed, file.Name)
+        }
+    }
+    return failed
+}
+
+// Indicate whether a release asset has the same SHA-256 checksum as the file.
+//  NOTE: the asset is streamed from its download location (with a client
+//  which does not send the GitHub authorization there).
+func sameAssetContent(client *Client, name string, id int64, file ProjFile) bool {
+    src, err := file.Open()
+    if log.ErrorValue(err) != nil {
+        return false
+    }
+    want, err := sha256Hash(src)
+    if log.ErrorValue(err) != nil {
+        return false
+    }
+    srv := client.ptr.Repositories
+    src, _, err = srv.DownloadReleaseAsset(ctx, ORG, name, id, http.DefaultClient)
+    if log.ErrorValue(err) != nil {
+        return false
+    }
+    have, err := sha256Hash(src)
+    if log.ErrorValue(err) != nil {
+        return false
+    }
+    return have == want
+}
+
+// The SHA-256 hash (as a hex string) of content, which is closed when done.
+func sha256Hash(src io.ReadCloser) (string, error) {
+    defer src.Close()
+    hash := sha256.New()
+    if _, err := io.Copy(hash, src); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(hash.Sum(nil)), nil
+}
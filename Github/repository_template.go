@@ -40,8 +40,7 @@ func RepoTemplateDataAsRepository(req *github.TemplateRepoRequest) *github.Repos
 
 // Create a new repository from a template repository.
 func createRepoFromTemplate(client *Client, templateName string, data *github.TemplateRepoRequest) (result *Repository) {
-    repo, rsp, err := client.ptr.Repositories.CreateFromTemplate(ctx, ORG, templateName, data)
-    extractRateLimit(rsp)
+    repo, _, err := client.ptr.Repositories.CreateFromTemplate(ctx, ORG, templateName, data)
     if log.ErrorValue(err) == nil {
         result = AsRepositoryType(client, repo)
     }
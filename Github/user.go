@@ -5,6 +5,8 @@
 package Github
 
 import (
+	"lib.virginia.edu/agita/log"
+
 	"github.com/google/go-github/v69/github"
 )
 
@@ -42,6 +44,21 @@ func GetUser(client *Client, login string) *User {
     }
 }
 
+// Indicate whether there is a GitHub account with the given login.
+//  NOTE: returns false if the account could not be checked.
+func UserExists(client *Client, login string) bool {
+    if client == nil { client = MainClient() }
+    return userExists(client.ptr, login)
+}
+
+// Indicate whether the GitHub account is a member of ORG.
+//  NOTE: returns false if the membership could not be checked.
+func IsOrgMember(client *Client, login string) bool {
+    if client == nil { client = MainClient() }
+    member, _, err := client.ptr.Organizations.IsMember(ctx, ORG, login)
+    return (log.ErrorValue(err) == nil) && member
+}
+
 // ============================================================================
 // Internal members
 // ============================================================================
@@ -56,6 +73,16 @@ func (u *User) initUser(user *github.User) *User {
 // Exported members
 // ============================================================================
 
+// The full name given in the user's profile.
+func (u *User) Name() string {
+    return u.ptr.GetName()
+}
+
+// The public email address given in the user's profile.
+func (u *User) Email() string {
+    return u.ptr.GetEmail()
+}
+
 // Get all GitHub Organization objects for the user.
 func (u *User) Orgs() []*github.Organization {
     items, _ := getUserOrgs(u.client.ptr, u.Login)
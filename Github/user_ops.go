@@ -6,6 +6,7 @@ package Github
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"lib.virginia.edu/agita/log"
@@ -52,14 +53,22 @@ func UserLabels(users []*github.User) []string {
 //  NOTE: panics if the user does not match `login`.
 //  NOTE: returns nil on error
 func getUser(client *github.Client, login string) *github.User {
-    user, rsp, err := client.Users.Get(ctx, login)
-    extractRateLimit(rsp)
+    user, _, err := client.Users.Get(ctx, login)
     if (log.ErrorValue(err) != nil) || !validateUser(user, login) {
         user = nil
     }
     return user
 }
 
+// Indicate whether there is a GitHub account with the given login.
+func userExists(client *github.Client, login string) bool {
+    _, rsp, err := client.Users.Get(ctx, login)
+    if (rsp != nil) && (rsp.StatusCode == http.StatusNotFound) {
+        return false
+    }
+    return log.ErrorValue(err) == nil
+}
+
 // Ensure that `user` has the given `login`.
 //  NOTE: either panics or returns true
 func validateUser(user *github.User, login string) bool {
@@ -8,24 +8,44 @@ import (
 	"io"
 
 	"lib.virginia.edu/agita/log"
+
+	"github.com/andygrunwald/go-jira"
 )
 
+// ============================================================================
+// Exported types
+// ============================================================================
+
+type Attachment = jira.Attachment
+
 // ============================================================================
 // Exported functions
 // ============================================================================
 
 // Get the content of the indicated attachment.
+//  NOTE: the whole attachment is held in memory; use OpenAttachment for large
+//  attachments.
 func DownloadAttachment(client *Client, attachmentId string) string {
-    if client == nil {
-        client = MainClient()
-    }
-    rsp, err := client.ptr.Issue.DownloadAttachment(attachmentId)
+    body, err := OpenAttachment(client, attachmentId)
     if log.ErrorValue(err) == nil {
-        defer rsp.Body.Close()
-        bytes, err := io.ReadAll(rsp.Body)
+        defer body.Close()
+        bytes, err := io.ReadAll(body)
         if log.ErrorValue(err) == nil {
             return string(bytes)
         }
     }
     return ""
 }
+
+// Get a stream of the content of the indicated attachment.
+//  NOTE: the caller must close the result.
+func OpenAttachment(client *Client, attachmentId string) (io.ReadCloser, error) {
+    if client == nil {
+        client = MainClient()
+    }
+    rsp, err := client.ptr.Issue.DownloadAttachment(attachmentId)
+    if err != nil {
+        return nil, err
+    }
+    return rsp.Body, nil
+}
@@ -0,0 +1,234 @@
+// Jira/board.go
+//
+// Jira Agile boards and their sprints, which are accessed through the Jira
+// Agile REST API.
+//
+// @see https://docs.atlassian.com/jira-software/REST/latest/
+
+package Jira
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/log"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+type Sprint = jira.Sprint
+
+// A column of a Jira board.
+type BoardColumn struct {
+    Name     string
+    Statuses []string   // IDs of the Jira statuses shown in the column.
+}
+
+// A Jira board with the state of the issues shown on it.
+type Board struct {
+    ID      int
+    Name    string
+    Type    string                      // "scrum" or "kanban".
+    Columns []BoardColumn               // In board order.
+    Sprints []Sprint                    // In the order given by Jira.
+    Issues  []IssueKey                  // In rank order.
+    Column  map[IssueKey]string         // The name of the column of each issue.
+    Sprint  map[IssueKey]int            // The ID of the latest sprint of each issue.
+    Values  map[IssueKey]map[string]any // Requested custom field values by name.
+}
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The Jira board type which has sprints.
+const SCRUM_BOARD = "scrum"
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// Get the boards which show issues of the project, along with their columns,
+// sprints and the placement of their issues.  The values of the named custom
+// fields are also fetched for each issue on a board.
+//  NOTE: may return partial results on error
+func (p *Project) Boards(fields ...string) []Board {
+    if noProject(p) { return []Board{} }
+    client := p.client.ptr
+    items  := getBoards(client, p.ptr.Key)
+    ids    := []string{"status"}
+    for _, name := range fields {
+        if id := FieldID(name); id != "" {
+            ids = append(ids, id)
+        }
+    }
+    result := make([]Board, 0, len(items))
+    for _, item := range items {
+        board := Board{
+            ID:      item.ID,
+            Name:    item.Name,
+            Type:    item.Type,
+            Columns: getBoardColumns(client, item.ID),
+            Sprints: []Sprint{},
+            Issues:  []IssueKey{},
+            Column:  map[IssueKey]string{},
+            Sprint:  map[IssueKey]int{},
+            Values:  map[IssueKey]map[string]any{},
+        }
+        column := map[string]string{}
+        for _, col := range board.Columns {
+            for _, status := range col.Statuses {
+                column[status] = col.Name
+            }
+        }
+        for _, issue := range getBoardIssues(client, item.ID, ids) {
+            board.Issues = append(board.Issues, issue.Key)
+            if issue.Fields == nil {
+                continue
+            }
+            if issue.Fields.Status != nil {
+                if name, found := column[issue.Fields.Status.ID]; found {
+                    board.Column[issue.Key] = name
+                }
+            }
+            if len(fields) > 0 {
+                board.Values[issue.Key] = customFields(issue.Fields.Unknowns, nil)
+            }
+        }
+        if item.Type == SCRUM_BOARD {
+            board.Sprints = getSprints(client, item.ID)
+            for _, sprint := range board.Sprints {
+                for _, issue := range getSprintIssues(client, sprint.ID) {
+                    board.Sprint[issue.Key] = sprint.ID
+                }
+            }
+        }
+        result = append(result, board)
+    }
+    return result
+}
+
+// ============================================================================
+// Internal types
+// ============================================================================
+
+// A page of issues returned by the Jira Agile REST API.
+type agileIssues struct {
+    StartAt    int          `json:"startAt"`
+    MaxResults int          `json:"maxResults"`
+    Total      int          `json:"total"`
+    Issues     []jira.Issue `json:"issues"`
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Get all boards which show issues of the indicated project.
+//  NOTE: may return partial results on error
+func getBoards(client *jira.Client, project ProjKey) []jira.Board {
+    result := []jira.Board{}
+    for {
+        urlStr   := fmt.Sprintf("rest/agile/1.0/board?projectKeyOrId=%s&startAt=%d", project, len(result))
+        req, err := client.NewRequest("GET", urlStr, nil)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        buffer := jira.BoardsList{}
+        _, err = client.Do(req, &buffer)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        result = append(result, buffer.Values...)
+        if buffer.IsLast || (len(buffer.Values) == 0) {
+            break
+        }
+    }
+    return result
+}
+
+// Get the columns of the indicated board.
+//  NOTE: returns an empty result on error
+func getBoardColumns(client *jira.Client, board int) []BoardColumn {
+    config, _, err := client.Board.GetBoardConfiguration(board)
+    if (log.ErrorValue(err) != nil) || (config == nil) {
+        return []BoardColumn{}
+    }
+    result := make([]BoardColumn, 0, len(config.ColumnConfig.Columns))
+    for _, col := range config.ColumnConfig.Columns {
+        column := BoardColumn{Name: col.Name, Statuses: []string{}}
+        for _, status := range col.Status {
+            column.Statuses = append(column.Statuses, status.ID)
+        }
+        result = append(result, column)
+    }
+    return result
+}
+
+// Get all sprints of the indicated board.
+//  NOTE: may return partial results on error
+func getSprints(client *jira.Client, board int) []Sprint {
+    result := []Sprint{}
+    for {
+        urlStr   := fmt.Sprintf("rest/agile/1.0/board/%d/sprint?startAt=%d", board, len(result))
+        req, err := client.NewRequest("GET", urlStr, nil)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        buffer := jira.SprintsList{}
+        _, err = client.Do(req, &buffer)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        result = append(result, buffer.Values...)
+        if buffer.IsLast || (len(buffer.Values) == 0) {
+            break
+        }
+    }
+    return result
+}
+
+// Get the issues shown on the indicated board in rank order.
+//  NOTE: only the indicated fields of each issue are returned
+//  NOTE: may return partial results on error
+func getBoardIssues(client *jira.Client, board int, fields []string) []jira.Issue {
+    urlStr := fmt.Sprintf("rest/agile/1.0/board/%d/issue?fields=%s", board, strings.Join(fields, ","))
+    return getAgileIssues(client, urlStr)
+}
+
+// Get the issues of the indicated sprint.
+//  NOTE: only the "status" field of each issue is returned
+//  NOTE: may return partial results on error
+func getSprintIssues(client *jira.Client, sprint int) []jira.Issue {
+    urlStr := fmt.Sprintf("rest/agile/1.0/sprint/%d/issue?fields=status", sprint)
+    return getAgileIssues(client, urlStr)
+}
+
+// Get issues from a Jira Agile REST API endpoint, requesting successive pages
+// until all have been received.
+//  NOTE: may return partial results on error
+func getAgileIssues(client *jira.Client, urlStr string) []jira.Issue {
+    result := []jira.Issue{}
+    for {
+        pageUrl  := fmt.Sprintf("%s&startAt=%d&maxResults=%d", urlStr, len(result), MAX_PER_PAGE)
+        req, err := client.NewRequest("GET", pageUrl, nil)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        buffer := agileIssues{}
+        _, err = client.Do(req, &buffer)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        result = append(result, buffer.Issues...)
+        if (len(buffer.Issues) == 0) || (len(result) >= buffer.Total) {
+            break
+        }
+    }
+    return slices.DeleteFunc(result, func(i jira.Issue) bool { return i.Key == "" })
+}
@@ -5,7 +5,9 @@ package Jira
 import (
 	"net/http"
 	"net/url"
+	"sync"
 
+	"lib.virginia.edu/agita/limiter"
 	"lib.virginia.edu/agita/log"
 
 	"github.com/andygrunwald/go-jira"
@@ -24,7 +26,8 @@ type Client struct {
 // Internal variables
 // ============================================================================
 
-var mainClient *Client
+var mainClient      *Client
+var mainClientMutex sync.Mutex
 
 // ============================================================================
 // Exported functions
@@ -32,6 +35,8 @@ var mainClient *Client
 
 // The default client used for application objects which do not specify one.
 func MainClient() *Client {
+    mainClientMutex.Lock()
+    defer mainClientMutex.Unlock()
     if mainClient == nil {
         mainClient = NewClient()
     }
@@ -42,7 +47,8 @@ func MainClient() *Client {
 // Jira at BASE_URL.
 func NewClient() (result *Client) {
     token := authToken()
-    httpClient := &http.Client{Transport: &jira.PATAuthTransport{Token: token}}
+    limited    := limiter.Jira.Transport(nil)
+    httpClient := &http.Client{Transport: &jira.PATAuthTransport{Token: token, Transport: limited}}
     client, err := jira.NewClient(httpClient, BASE_URL)
     if log.ErrorValue(err) == nil {
         result = &Client{ptr: client}
@@ -0,0 +1,210 @@
+// Jira/field.go
+//
+// Jira issue field definitions, including custom fields whose identifiers
+// vary between Jira instances.
+
+package Jira
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"lib.virginia.edu/agita/log"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The name of the Jira Software custom field holding the key of an issue's epic.
+const EPIC_LINK_FIELD = "Epic Link"
+
+// The Jira issue type of an epic.
+const EPIC_TYPE = "Epic"
+
+// The prefix of the ID of a Jira custom field.
+const CUSTOM_FIELD_PREFIX = "customfield_"
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// Mapping of field name to field ID for all Jira issue fields.
+var FieldByName map[string]string
+
+// Mapping of field ID to field name for all Jira issue fields, including the
+// names discovered from issues fetched with "expand=names".
+var FieldNameByID map[string]string
+
+// ============================================================================
+// Internal variables
+// ============================================================================
+
+// Guards access to FieldNameByID after initialization.
+var fieldNamesMutex sync.Mutex
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Get the ID of the named Jira field (e.g. "customfield_10100").
+//  NOTE: returns blank if there is no such field
+func FieldID(name string) string {
+    return FieldByName[name]
+}
+
+// Get the name of the Jira field with the given ID.
+//  NOTE: returns the ID itself if the field is not known
+func FieldName(id string) string {
+    fieldNamesMutex.Lock()
+    defer fieldNamesMutex.Unlock()
+    if name := FieldNameByID[id]; name != "" {
+        return name
+    }
+    return id
+}
+
+// Indicate whether the field ID is for a Jira custom field.
+func IsCustomField(id string) bool {
+    return strings.HasPrefix(id, CUSTOM_FIELD_PREFIX)
+}
+
+// Have Search() return the named fields in addition to SEARCH_FIELDS.
+//  NOTE: names of fields which are not defined are ignored
+func AddSearchFields(names ...string) {
+    for _, name := range names {
+        if id := FieldID(name); (id != "") && !slices.Contains(SEARCH_FIELDS, id) {
+            SEARCH_FIELDS = append(SEARCH_FIELDS, id)
+        }
+    }
+}
+
+// Render a custom field value as a string.  Option, user, and version values
+// are rendered by their displayed value; lists are rendered as a comma-
+// separated list of their elements.
+//  NOTE: returns an empty string for a nil value
+func FieldString(value any) string {
+    switch v := value.(type) {
+        case nil:
+            return ""
+        case string:
+            return v
+        case float64:
+            return strconv.FormatFloat(v, 'f', -1, 64)
+        case bool:
+            return strconv.FormatBool(v)
+        case []any:
+            res := make([]string, 0, len(v))
+            for _, item := range v {
+                if str := FieldString(item); str != "" {
+                    res = append(res, str)
+                }
+            }
+            return strings.Join(res, ", ")
+        case map[string]any:
+            for _, key := range []string{"value", "displayName", "name", "key"} {
+                if str, ok := v[key].(string); ok && (str != "") {
+                    if child, ok := v["child"]; ok {
+                        str += " - " + FieldString(child)
+                    }
+                    return str
+                }
+            }
+            return ""
+        default:
+            return fmt.Sprint(v)
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Get the non-nil custom field values by field name, preferring the names
+// reported by Jira for the issue.
+func customFields(unknowns map[string]any, names map[string]string) map[string]any {
+    result := map[string]any{}
+    for id, value := range unknowns {
+        if (value == nil) || !IsCustomField(id) {
+            continue
+        }
+        name := names[id]
+        if name == "" {
+            name = FieldName(id)
+        }
+        result[name] = value
+    }
+    return result
+}
+
+// Record field names reported by Jira for an issue fetched with
+// "expand=names".
+func discoverFields(names map[string]string) {
+    fieldNamesMutex.Lock()
+    defer fieldNamesMutex.Unlock()
+    for id, name := range names {
+        if _, known := FieldNameByID[id]; !known && (name != "") {
+            FieldNameByID[id] = name
+        }
+    }
+}
+
+// Get all issue field definitions for the Jira referenced by the client.
+//  NOTE: may return an empty result on error
+func getFields(client *jira.Client) []jira.Field {
+    result, _, err := client.Field.GetList()
+    if log.ErrorValue(err) != nil {
+        result = []jira.Field{}
+    }
+    return result
+}
+
+// ============================================================================
+// Internal functions - initialization
+// ============================================================================
+
+// Get all field definitions through a new client.
+func fieldDefinitions() []jira.Field {
+    if client := NewClient(); !noClient(client) {
+        return getFields(client.ptr)
+    }
+    return []jira.Field{}
+}
+
+// Generate a mapping of field name to field ID of all fields.
+func fieldByName(items []jira.Field) map[string]string {
+    result := make(map[string]string, len(items))
+    for _, field := range items {
+        if _, dup := result[field.Name]; !dup {
+            result[field.Name] = field.ID
+        }
+    }
+    return result
+}
+
+// Generate a mapping of field ID to field name of all fields.
+func fieldNameByID(items []jira.Field) map[string]string {
+    result := make(map[string]string, len(items))
+    for _, field := range items {
+        result[field.ID] = field.Name
+    }
+    return result
+}
+
+// ============================================================================
+// Module initialization
+// ============================================================================
+
+// Initialize variables related to Jira fields.
+func setupField() {
+    if (FieldByName == nil) || (FieldNameByID == nil) {
+        items := fieldDefinitions()
+        FieldByName   = fieldByName(items)
+        FieldNameByID = fieldNameByID(items)
+    }
+}
@@ -36,6 +36,7 @@ type AppObject interface {
 func Initialize() bool {
     setupClient()
     setupProject()
+    setupField()
     setupIssue()
     setupComment()
     return true
@@ -17,6 +17,23 @@ type Issue struct {
     client *Client
 }
 
+// A relationship between an issue and another Jira issue.
+type IssueLink struct {
+    Type     string     // Link type name (e.g. "Blocks").
+    Relation string     // From the point of view of the issue (e.g. "is blocked by").
+    Outward  bool       // The issue is the source of the link.
+    Key      IssueKey   // The other issue.
+}
+
+// A change to a field of an issue recorded in its Jira changelog.
+type Change struct {
+    Author   string     // Jira account of the user making the change.
+    Created  string     // Jira timestamp of the change.
+    Field    string
+    From     string     // Displayed value before the change.
+    To       string     // Displayed value after the change.
+}
+
 // ============================================================================
 // Exported functions
 // ============================================================================
@@ -115,6 +132,13 @@ func (i *Issue) Status() string {
     return i.ptr.Fields.Status.Name
 }
 
+// Return the key of the underlying Status category ("new", "indeterminate", or
+// "done") or an empty string.
+func (i *Issue) StatusCategory() string {
+    if noFields(i) || (i.ptr.Fields.Status == nil) { return "" }
+    return i.ptr.Fields.Status.StatusCategory.Key
+}
+
 // Return the underlying Assignee value or an empty string.
 func (i *Issue) Assignee() string {
     if noFields(i) { return "" }
@@ -127,12 +151,109 @@ func (i *Issue) Labels() []string {
     return i.ptr.Fields.Labels
 }
 
+// Return the names of the underlying Components.
+func (i *Issue) Components() []string {
+    if noFields(i) { return []string{} }
+    return componentStrings(i.ptr.Fields.Components)
+}
+
 // Return the underlying Attachments.
 func (i *Issue) Attachments() []*jira.Attachment {
     if noFields(i) { return []*jira.Attachment{} }
     return i.ptr.Fields.Attachments
 }
 
+// Return the key of the underlying Parent or an empty string.
+func (i *Issue) Parent() IssueKey {
+    if noFields(i) || (i.ptr.Fields.Parent == nil) { return "" }
+    return i.ptr.Fields.Parent.Key
+}
+
+// Return the keys of the underlying Subtasks.
+func (i *Issue) Subtasks() []IssueKey {
+    if noFields(i) { return []IssueKey{} }
+    res := make([]IssueKey, 0, len(i.ptr.Fields.Subtasks))
+    for _, subtask := range i.ptr.Fields.Subtasks {
+        if subtask != nil {
+            res = append(res, subtask.Key)
+        }
+    }
+    return res
+}
+
+// Return the names of the underlying FixVersions.
+func (i *Issue) FixVersions() []string {
+    if noFields(i) { return []string{} }
+    res := make([]string, 0, len(i.ptr.Fields.FixVersions))
+    for _, version := range i.ptr.Fields.FixVersions {
+        if version != nil {
+            res = append(res, version.Name)
+        }
+    }
+    return res
+}
+
+// Return the key of the epic to which the issue belongs or an empty string.
+//  NOTE: Jira Server reports this through the "Epic Link" custom field.
+func (i *Issue) Epic() IssueKey {
+    if noFields(i) { return "" }
+    if epic := i.ptr.Fields.Epic; (epic != nil) && (epic.Key != "") {
+        return epic.Key
+    }
+    if id := FieldID(EPIC_LINK_FIELD); id != "" {
+        if key, ok := i.ptr.Fields.Unknowns[id].(string); ok {
+            return key
+        }
+    }
+    return ""
+}
+
+// Return the underlying Duedate value or nilDate.
+func (i *Issue) Duedate() Date {
+    if noFields(i) { return nilDate }
+    return i.ptr.Fields.Duedate
+}
+
+// Return the relationships described by the underlying IssueLinks.
+func (i *Issue) Links() []IssueLink {
+    if noFields(i) { return []IssueLink{} }
+    res := make([]IssueLink, 0, len(i.ptr.Fields.IssueLinks))
+    for _, link := range i.ptr.Fields.IssueLinks {
+        switch {
+            case link == nil:
+                continue
+            case link.OutwardIssue != nil:
+                res = append(res, IssueLink{link.Type.Name, link.Type.Outward, true, link.OutwardIssue.Key})
+            case link.InwardIssue != nil:
+                res = append(res, IssueLink{link.Type.Name, link.Type.Inward, false, link.InwardIssue.Key})
+        }
+    }
+    return res
+}
+
+// Return the values of the custom fields of the issue by field name.
+//  NOTE: only custom fields returned by the request are included.
+func (i *Issue) CustomFields() map[string]any {
+    if noFields(i) { return map[string]any{} }
+    return customFields(i.ptr.Fields.Unknowns, i.ptr.Names)
+}
+
+// Return the field changes recorded in the underlying Changelog, in the
+// (chronological) order given by Jira.
+//  NOTE: the changelog is only present if it was expanded by the request.
+func (i *Issue) Changes() []Change {
+    if noIssue(i) || (i.ptr.Changelog == nil) { return []Change{} }
+    res := []Change{}
+    for _, history := range i.ptr.Changelog.Histories {
+        author := Account(&history.Author)
+        for _, item := range history.Items {
+            change := Change{author, history.Created, item.Field, item.FromString, item.ToString}
+            res = append(res, change)
+        }
+    }
+    return res
+}
+
 // ============================================================================
 // Internal functions
 // ============================================================================
@@ -170,6 +291,25 @@ func (i *Issue) Comment(id CommentId) *Comment {
     return GetCommentById(i.client, i.ptr.Key, id)
 }
 
+// ============================================================================
+// Exported methods - watchers
+// ============================================================================
+
+// Get the Jira accounts of the users watching the issue.
+//  NOTE: returns an empty result on error
+func (i *Issue) Watchers() []string {
+    if (i.client == nil) || (i.client.ptr == nil) { panic(ERR_NIL_CLIENT) }
+    if (i.ptr    == nil) || (i.ptr.Key    == "")  { panic(ERR_NO_ISSUE) }
+    items  := getWatchers(i.client.ptr, i.ptr.Key)
+    result := make([]string, 0, len(items))
+    for _, watcher := range items {
+        if watcher.Name != "" {
+            result = append(result, watcher.Name)
+        }
+    }
+    return result
+}
+
 // ============================================================================
 // Exported methods - rendering
 // ============================================================================
@@ -286,16 +286,16 @@ var ISSUE_FIELDS_MARSHAL = map[string]bool{
     "Summary":                          true,
     "Creator":                          true,
     "Reporter":                         true,
-    "Components":                       ____,
+    "Components":                       true,
     "Status":                           true,
     "Progress":                         true,
     "AggregateProgress":                ____,
-    "TimeTracking":                     ____,
-    "TimeSpent":                        ____,
-    "TimeEstimate":                     ____,
-    "TimeOriginalEstimate":             ____,
+    "TimeTracking":                     true,
+    "TimeSpent":                        true,
+    "TimeEstimate":                     true,
+    "TimeOriginalEstimate":             true,
     "Worklog":                          ____,
-    "IssueLinks":                       ____,
+    "IssueLinks":                       true,
     "Comments":                         ____,
     "FixVersions":                      true,
     "AffectsVersions":                  true,
@@ -304,10 +304,10 @@ var ISSUE_FIELDS_MARSHAL = map[string]bool{
     "Attachments":                      true,
     "Epic":                             true,
     "Sprint":                           ____,
-    "Parent":                           ____,
-    "AggregateTimeOriginalEstimate":    ____,
-    "AggregateTimeSpent":               ____,
-    "AggregateTimeEstimate":            ____,
+    "Parent":                           true,
+    "AggregateTimeOriginalEstimate":    true,
+    "AggregateTimeSpent":               true,
+    "AggregateTimeEstimate":            true,
     "Unknowns":                         ____,
 }
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"lib.virginia.edu/agita/log"
 	"lib.virginia.edu/agita/util"
@@ -22,6 +24,18 @@ import (
 // type IssueId  = MapId
 type IssueKey = string
 
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// Layout for date/time values in JQL queries.
+const JQL_TIME_FORMAT = "2006/01/02 15:04"
+
+// The furthest behind UTC that any time zone may be.  If the time zone in which
+// Jira reads JQL date/time values is not known, times are given in this zone so
+// that they are never later than intended.
+const JQL_EARLIEST_OFFSET = -12 * 60 * 60
+
 // ============================================================================
 // Exported variables
 // ============================================================================
@@ -29,12 +43,26 @@ type IssueKey = string
 // Set with searchFields() on module initialization.
 var SEARCH_FIELDS []string
 
+// Additional information returned by Search() for each issue.
+//  NOTE: This is for the sake of getting the issue history for Changes() and
+//  the names of custom fields for CustomFields().
+var SEARCH_EXPAND = "changelog,names"
+
+// ============================================================================
+// Internal variables
+// ============================================================================
+
+// The time zone in which Jira reads JQL date/time values; set by jqlZone().
+var jqlLocation      *time.Location
+var jqlLocationMutex sync.Mutex
+
 // ============================================================================
 // Internal functions
 // ============================================================================
 
 // Use ISSUE_FIELDS_MARSHAL to determine which fields Search() should return.
 //  NOTE: This is for the sake of getting "attachment" returned.
+//  NOTE: The "Epic Link" custom field is included if it is defined.
 func searchFields() []string {
     Type   := reflect.TypeOf(jira.IssueFields{})
     count  := Type.NumField()
@@ -52,6 +80,9 @@ func searchFields() []string {
             result = append(result, tag)
         }
 	}
+    if id := FieldID(EPIC_LINK_FIELD); id != "" {
+        result = append(result, id)
+    }
     return result
 }
 
@@ -66,6 +97,14 @@ func getIssues(client *jira.Client, project ProjKey) []jira.Issue {
 //  NOTE: JQL will fail if a stated issue does not exist
 //  NOTE: PROJ-0 and PROJ-1 will be ignored for `minKey`
 func getIssueRange(client *jira.Client, project ProjKey, minKey, maxKey IssueKey) (result []jira.Issue) {
+    return getIssueRangeSince(client, project, minKey, maxKey, time.Time{})
+}
+
+// Get issues for the indicated project, between keys inclusive, which were
+// created or updated at or after `since` (unless it is the zero time).
+//  NOTE: a new or modified comment counts as an update to its issue
+//  NOTE: may return partial results on error
+func getIssueRangeSince(client *jira.Client, project ProjKey, minKey, maxKey IssueKey, since time.Time) (result []jira.Issue) {
 
     // Build the query based on the indicated issue range.
     jql := fmt.Sprintf("project = %s", project)
@@ -84,10 +123,52 @@ func getIssueRange(client *jira.Client, project ProjKey, minKey, maxKey IssueKey
         }
         jql += fmt.Sprintf(" AND Key <= %q", max)
     }
+    if !since.IsZero() {
+        // JQL dates have minute resolution in the user's profile time zone.
+        jql += fmt.Sprintf(" AND updated >= %q", since.In(jqlZone(client)).Format(JQL_TIME_FORMAT))
+    }
     jql += " ORDER BY Key Asc"
+    return searchIssues(client, jql)
+}
+
+// The time zone in which Jira reads JQL date/time values, which is that of the
+// profile of the authenticated user.
+//  NOTE: if the profile time zone cannot be determined, the zone which is
+//  furthest behind UTC is used so that a query never starts too late.
+func jqlZone(client *jira.Client) *time.Location {
+    jqlLocationMutex.Lock()
+    defer jqlLocationMutex.Unlock()
+    if jqlLocation != nil {
+        return jqlLocation
+    }
+    var err error
+    if user, _, e := client.User.GetSelf(); e != nil {
+        err = e
+    } else if user.TimeZone == "" {
+        err = fmt.Errorf("no time zone in user profile")
+    } else {
+        jqlLocation, err = time.LoadLocation(user.TimeZone)
+    }
+    if err != nil {
+        log.Warn("JQL time zone unknown: %v", err)
+        jqlLocation = time.FixedZone("JQL", JQL_EARLIEST_OFFSET)
+    }
+    return jqlLocation
+}
+
+// Get issues for the indicated project which are of the given issue type.
+//  NOTE: may return partial results on error
+func getIssuesOfType(client *jira.Client, project ProjKey, issueType string) []jira.Issue {
+    jql := fmt.Sprintf("project = %s AND issuetype = %q ORDER BY Key Asc", project, issueType)
+    return searchIssues(client, jql)
+}
+
+// Get the issues selected by the JQL query.
+//  NOTE: may return partial results on error
+func searchIssues(client *jira.Client, jql string) (result []jira.Issue) {
 
     // Specify issue fields to be returned.
-    opt := &jira.SearchOptions{Fields: SEARCH_FIELDS, MaxResults: MAX_PER_PAGE}
+    opt := &jira.SearchOptions{Fields: SEARCH_FIELDS, Expand: SEARCH_EXPAND, MaxResults: MAX_PER_PAGE}
 
     // Get items, possibly across multiple search response pages.
     for last, total := 0, 1; last < total; {
@@ -109,15 +190,33 @@ func getIssueRange(client *jira.Client, project ProjKey, minKey, maxKey IssueKey
     return
 }
 
+// Get the users watching the indicated issue.
+//  NOTE: returns an empty result on error
+//  NOTE: jira.IssueService.GetWatchers is not used because it looks up each
+//  watcher by account ID, which Jira Server does not support.
+func getWatchers(client *jira.Client, issue IssueKey) []*jira.Watcher {
+    urlStr   := fmt.Sprintf("rest/api/2/issue/%s/watchers", issue)
+    req, err := client.NewRequest("GET", urlStr, nil)
+    if log.ErrorValue(err) == nil {
+        buffer := jira.Watches{}
+        _, err = client.Do(req, &buffer)
+        if log.ErrorValue(err) == nil {
+            return buffer.Watchers
+        }
+    }
+    return []*jira.Watcher{}
+}
+
 // Get the issue with the given issue key.
 //  NOTE: returns nil on error
 func getIssueByKey(client *jira.Client, key IssueKey) (result *jira.Issue) {
-    urlStr := fmt.Sprintf("rest/api/2/issue/%s", key)
+    urlStr := fmt.Sprintf("rest/api/2/issue/%s?expand=%s", key, SEARCH_EXPAND)
     req, err := client.NewRequest("GET", urlStr, nil)
     if log.ErrorValue(err) == nil {
         buffer := jira.Issue{}
         _, err = client.Do(req, &buffer)
         if log.ErrorValue(err) == nil {
+            discoverFields(buffer.Names)
             result = &buffer
         }
     }
@@ -186,6 +285,12 @@ func issueFieldsDetails(i *jira.Issue) string {
     Created        := TimeString(f.Created)
     DueDate        := DateString(f.Duedate)
     Updated        := TimeString(f.Updated)
+    Estimate       := f.TimeOriginalEstimate
+    Remaining      := f.TimeEstimate
+    Spent          := f.TimeSpent
+    AggEstimate    := f.AggregateTimeOriginalEstimate
+    AggRemaining   := f.AggregateTimeEstimate
+    AggSpent       := f.AggregateTimeSpent
 
     if f.Summary      != ""      { add("Summary",                       f.Summary) }
     if f.Expand       != ""      { add("Expand",                        f.Expand) }
@@ -202,16 +307,16 @@ func issueFieldsDetails(i *jira.Issue) string {
     if Updated        != NO_TIME { add("Updated",                       Updated) }
     if f.Creator      != nil     { add("Creator",                       UserLabel(f.Creator)) }
     if f.Reporter     != nil     { add("Reporter",                      UserLabel(f.Reporter)) }
-    if len(f.Components) > 0     { add("Components",                    f.Components) }
+    if len(f.Components) > 0     { add("Components",                    componentStrings(f.Components)) }
     if f.Status       != nil     { add("Status",                        f.Status.Name) }
     if f.Progress     != nil     { add("Progress",                     *f.Progress) }
     if false                     { add("AggregateProgress",            *f.AggregateProgress) }
-    if false                     { add("TimeTracking",                 *f.TimeTracking) }
-    if false                     { add("TimeSpent",                     f.TimeSpent) }
-    if false                     { add("TimeEstimate",                  f.TimeEstimate) }
-    if false                     { add("TimeOriginalEstimate",          f.TimeOriginalEstimate) }
-    if false                     { add("Worklog",                      *f.Worklog) }
-    if false                     { add("IssueLinks",                    f.IssueLinks) }
+    if f.TimeTracking != nil     { add("TimeTracking",                 *f.TimeTracking) }
+    if Spent          > 0        { add("TimeSpent",                     DurationString(Spent)) }
+    if Remaining      > 0        { add("TimeEstimate",                  DurationString(Remaining)) }
+    if Estimate       > 0        { add("TimeOriginalEstimate",          DurationString(Estimate)) }
+    if f.Worklog      != nil     { add("Worklog",                       worklogStrings(f.Worklog)) }
+    if len(f.IssueLinks) > 0     { add("IssueLinks",                    issueLinkStrings(f.IssueLinks)) }
     if false                     { add("Comments",                     *f.Comments) }
     if false                     { add("FixVersions",                   f.FixVersions) }
     if false                     { add("AffectsVersions",               f.AffectsVersions) }
@@ -220,11 +325,11 @@ func issueFieldsDetails(i *jira.Issue) string {
     if len(f.Attachments) > 0    { add("Attachments",                   f.Attachments) }
     if false                     { add("Epic",                         *f.Epic) }
     if false                     { add("Sprint",                       *f.Sprint) }
-    if false                     { add("Parent",                       *f.Parent) }
-    if false                     { add("AggregateTimeOriginalEstimate", f.AggregateTimeOriginalEstimate) }
-    if false                     { add("AggregateTimeSpent",            f.AggregateTimeSpent) }
-    if false                     { add("AggregateTimeEstimate",         f.AggregateTimeEstimate) }
-    if false                     { add("Unknowns",                      f.Unknowns) }
+    if f.Parent       != nil     { add("Parent",                        f.Parent.Key) }
+    if AggEstimate    > 0        { add("AggregateTimeOriginalEstimate", DurationString(AggEstimate)) }
+    if AggSpent       > 0        { add("AggregateTimeSpent",            DurationString(AggSpent)) }
+    if AggRemaining   > 0        { add("AggregateTimeEstimate",         DurationString(AggRemaining)) }
+    if len(f.Unknowns) > 0       { add("Unknowns",                      customFields(f.Unknowns, i.Names)) }
 
     if f.Description  != ""      { add("Description",                   f.Description) }
     if f.Comments     != nil     { add("Comments",                     *f.Comments) }
@@ -232,6 +337,33 @@ func issueFieldsDetails(i *jira.Issue) string {
     return strings.Join(res, "\n")
 }
 
+// Render components as their names.
+func componentStrings(components []*jira.Component) []string {
+    res := make([]string, 0, len(components))
+    for _, component := range components {
+        if component != nil {
+            res = append(res, component.Name)
+        }
+    }
+    return res
+}
+
+// Render issue links as "relation KEY" strings.
+func issueLinkStrings(links []*jira.IssueLink) []string {
+    res := make([]string, 0, len(links))
+    for _, link := range links {
+        switch {
+            case link == nil:
+                continue
+            case link.OutwardIssue != nil:
+                res = append(res, link.Type.Outward + " " + link.OutwardIssue.Key)
+            case link.InwardIssue != nil:
+                res = append(res, link.Type.Inward + " " + link.InwardIssue.Key)
+        }
+    }
+    return res
+}
+
 // ============================================================================
 // Module initialization
 // ============================================================================
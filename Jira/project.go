@@ -5,6 +5,8 @@
 package Jira
 
 import (
+	"time"
+
 	"github.com/andygrunwald/go-jira"
 )
 
@@ -66,6 +68,13 @@ func (p *Project) Name() string {
     return p.ptr.Name
 }
 
+// Get all versions defined for the project.
+//  NOTE: all returned elements are non-nil
+func (p *Project) Versions() []*Version {
+    if noProject(p) { return []*Version{} }
+    return getVersions(p.client.ptr, p.ptr.Key)
+}
+
 // ============================================================================
 // Internal functions - properties
 // ============================================================================
@@ -95,6 +104,21 @@ func (p *Project) GetIssues(minKey, maxKey IssueKey) []Issue {
     return p.makeIssues(items)
 }
 
+// Get issues for the indicated project, between keys inclusive, which have been
+// created or updated (including by comments) at or after the given time.
+//  NOTE: may return partial results on error
+func (p *Project) GetIssuesSince(minKey, maxKey IssueKey, since time.Time) []Issue {
+    items := getIssueRangeSince(p.client.ptr, p.ptr.Key, minKey, maxKey, since)
+    return p.makeIssues(items)
+}
+
+// Get all issues of the project which are epics.
+//  NOTE: may return partial results on error
+func (p *Project) Epics() []Issue {
+    items := getIssuesOfType(p.client.ptr, p.ptr.Key, EPIC_TYPE)
+    return p.makeIssues(items)
+}
+
 // Get the issue with the given issue key.
 //  NOTE: returns nil on error
 func (p *Project) GetIssue(key IssueKey) *Issue {
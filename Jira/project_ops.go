@@ -6,6 +6,7 @@ package Jira
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -23,6 +24,7 @@ import (
 
 type ProjId  = int
 type ProjKey = string
+type Version = jira.Version
 
 // ============================================================================
 // Exported variables
@@ -84,6 +86,19 @@ func getProjects(client *jira.Client) []*jira.Project {
     return result
 }
 
+// Get all versions defined for the indicated project.
+//  NOTE: all returned elements are non-nil
+func getVersions(client *jira.Client, key ProjKey) []*Version {
+    result   := []*Version{}
+    urlStr   := fmt.Sprintf("rest/api/2/project/%s/versions", key)
+    req, err := client.NewRequest("GET", urlStr, nil)
+    if log.ErrorValue(err) == nil {
+        _, err = client.Do(req, &result)
+        log.ErrorValue(err)
+    }
+    return slices.DeleteFunc(result, func(v *Version) bool { return v == nil })
+}
+
 // Get the project with the given project key.
 //  NOTE: returns nil on error
 func getProjectByKey(client *jira.Client, key ProjKey) *jira.Project {
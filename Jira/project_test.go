@@ -5,6 +5,7 @@ package Jira
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"lib.virginia.edu/agita/test"
 
@@ -174,6 +175,55 @@ func TestProject_Issues(t *testing.T) {
 	}
 }
 
+func TestProject_GetIssuesSince(t *testing.T) {
+    const fn = "Project.GetIssuesSince"
+
+	type fields struct {
+		ptr    *jira.Project
+		client *Client
+	}
+	type args struct {
+		since time.Time
+	}
+    type testCase struct {
+		name   string
+		fields fields
+		args   args
+		want   []Issue
+        err    string
+	}
+
+    client := TestClient
+    proj   := SampleProject(client)
+    Case   := func(idx int, since time.Time, want []Issue, err string) (tc testCase) {
+        tc.name   = test.CaseName(fn, idx)
+        tc.fields = fields{proj.ptr, client}
+        tc.args   = args{since}
+        tc.want   = want
+        tc.err    = err
+        return
+    }
+
+    issues := testWantIssues(SAMPLE_ISSUES...)
+    none   := testWantIssues()
+    future := time.Now().AddDate(1, 0, 0)
+    tests  := []testCase{
+        Case(0, time.Time{}, issues, ""),
+        Case(1, future,      none,   ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            defer test.EvaluatePanic(tt.name, tt.err, t)
+			proj := &Project{
+				ptr:    tt.fields.ptr,
+				client: tt.fields.client,
+			}
+            got := proj.GetIssuesSince("", "", tt.args.since)
+            testVerifyIssues(fn, got, tt.want, t)
+		})
+	}
+}
+
 func TestProject_GetIssue(t *testing.T) {
     const fn = "Project.GetIssue"
 
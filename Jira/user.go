@@ -4,113 +4,15 @@ package Jira
 
 import (
 	"fmt"
+	"net/url"
 
+	"lib.virginia.edu/agita/log"
+	"lib.virginia.edu/agita/users"
 	"lib.virginia.edu/agita/util"
 
 	"github.com/andygrunwald/go-jira"
 )
 
-// ============================================================================
-// Exported variables
-// ============================================================================
-
-// A mapping of Jira account to user full name.
-var JiraUser = map[string]string {
-    "adj5j":    "Tony Jones",
-    "ag5vy":    "Anne Gaynor",
-    "akl3b":    "Amber Reichert",
-    "arb5w":    "Ann Burns",
-    "au3vk":    "Ashish Upadhyaya",
-    "bcb4y":    "Brandon Butler",
-    "bds2mv":   "Ben Spector",
-    "bg9ba":    "Brenda Gunn",
-    "bga3d":    "Bethany Anderson",
-    "bmx7wf":   "Marco Battistella",
-    "bwb9f":    "Beth Blanton",
-    "bwo3db":   "Ben Ormond",
-    "cma4u":    "Carla Arton",
-    "cmm2t":    "Christina Deane",
-    "cmw5mc":   "Christopher Welte",
-    "csk5vf":   "Connor Kenaston",
-    "dhc4z":    "Doug Chestnut",
-    "dpg3k":    "Dave Goldstein",
-    "ecr2c":    "Ellen Ramsey",
-    "ege5vd":   "Molly Fair",
-    "eks4x":    "Eric Seidel",
-    "emg3b":    "Eliza Gilligan",
-    "er8fn":    "Liz Rapp",
-    "gmk3d":    "Ginny Kois",
-    "hdn9c":    "Hanni Nabahe",
-    "hmh8eq":   "Akari Hernandez",
-    "ja3nu":    "Joseph Azizi",
-    "jgc4q":    "Zeke Crater",
-    "jh9xp":    "Jill Heinze",
-    "jkb2b":    "Jeremy Boggs",
-    "jlj5aj":   "Jason Jordan",
-    "jlk4p":    "Jack Kelly",
-    "jmu2m":    "John Unsworth",
-    "jor2a":    "Jennifer Roper",
-    "jph9e":    "Jeff Hill",
-    "jtb4t":    "Jeremy Bartczak",
-    "ka7uz":    "Krystal Appiah",
-    "khj5c":    "Kristen Jensen",
-    "kmm6ef":   "Kara McClurken",
-    "lar4k":    "Leigh Rockey",
-    "ldc8n":    "Linda Vaughan",
-    "lf6f":     "Lou Foster",
-    "libx-sat": "SAT Team",
-    "lsc6v":    "Lorrie Chisholm",
-    "lw2cd":    "Lauren Work",
-    "lws4n":    "Lucie Stylianopoulos",
-    "lzl9b":    "Lauren Longwell",
-    "md5wz":    "Mike Durbin",
-    "mdw7p":    "Melanie Williams",
-    "mhm8m":    "Heather Riser",
-    "mhw8m":    "Mark Witteman",
-    "mrc2x":    "Marc Campbell",
-    "mwm7b":    "Molly Minturn",
-    "naw4t":    "Nestor Walker",
-    "nir4x":    "Nicole Royal",
-    "np6hd":    "Nitesh Parajuli",
-    "nqt2bq":   "Ryan Russo",
-    "pw7e":     "Peter Welch",
-    "rac8f":    "Rebecca Coleman",
-    "rah6w":    "Ric Hodges",
-    "rar6u":    "Renee Reighart",
-    "rcm7e":    "Rennie Mapp",
-    "rds4w":    "Rob Smith",
-    "rfg":      "Rebecca Garver",
-    "rh9ec":    "Bob Haschart",
-    "rmg6f":    "Bob Gartland",
-    "rpk2kn":   "Ryan Kelly",
-    "rsl6m":    "Robin Ruggaber",
-    "rwl":      "Ray Lubinsky",
-    "sah":      "Sherry Lake",
-    "sd3gz":    "Sue Donovan",
-    "sh2rd":    "Stephanie Hunter",
-    "smm3m":    "Sean McCord",
-    "sp7fg":    "Sony Prosper",
-    "spr7b":    "Sue Richeson",
-    "srv3f":    "Steven Villereal",
-    "sss3s":    "Susan Neal",
-    "stg2s":    "Stan Gunn",
-    "tnn7yc":   "Tho Nguyen",
-    "trb5f":    "Tammy Barbour",
-    "tss6n":    "Tim Stevens",
-    "tsh2k":    "Trillian Hosticka",
-    "tvf3c":    "Tracy Fewell",
-    "vdg8v":    "Dave Griles",
-    "vlk4n":    "Vince Kois",
-    "wdw5ch":   "Will Wyatt",
-    "wkb5j":    "Winston Barham",
-    "wmr5a":    "Will Rourk",
-    "wxf2nb":   "Ruobing Su",
-    "xjk5yt":   "Nora Wilkerson",
-    "xw5d":     "Xiaoming Wang",
-    "ys2ck":    "Yukesh Sitoula",
-    "ys2n":     "Yuji Shinozaki",
-}
-
 // ============================================================================
 // Exported types
 // ============================================================================
@@ -120,20 +22,38 @@ type UserArg interface {
     jira.User | *jira.User
 }
 
+// The profile of a Jira account.
+type UserProfile struct {
+    Account string  `json:"account"`
+    Name    string  `json:"name"`       // Display name.
+    Email   string  `json:"email"`
+    Active  bool    `json:"active"`     // False if the account is disabled.
+}
+
 // ============================================================================
 // Exported functions
 // ============================================================================
 
 // Return the Jira account name in a form that includes the user's full name
-// if included in the JiraUser map.
+// if included in the user directory.
 func AppendFullName(jiraAccount string) string {
-    if fullName := JiraUser[jiraAccount]; fullName == "" {
+    if fullName := users.FullName(jiraAccount); fullName == "" {
         return jiraAccount
     } else {
         return fmt.Sprintf("%s (%s)", jiraAccount, fullName)
     }
 }
 
+// Get the profile of a Jira account.
+//  NOTE: returns nil on error (e.g. if the account no longer exists).
+func GetUserProfile(client *Client, account string) *UserProfile {
+    if client == nil { client = MainClient() }
+    if user := getUser(client.ptr, account); user != nil {
+        return &UserProfile{user.Name, user.DisplayName, user.EmailAddress, user.Active}
+    }
+    return nil
+}
+
 // Return a non-blank string representing the Jira account for the argument.
 func UserLabel[T UserArg](arg T) string {
     name := Account(arg)
@@ -168,3 +88,24 @@ func SameAccount[T1, T2 any](arg1 T1, arg2 T2) bool {
         return Account(arg1) == Account(arg2)
     }
 }
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Get the Jira User object for an account.
+//  NOTE: returns nil on error
+//  NOTE: jira.UserService.Get is not used because it looks up the account by
+//  account ID, which Jira Server does not support.
+func getUser(client *jira.Client, account string) (result *jira.User) {
+    urlStr   := "rest/api/2/user?username=" + url.QueryEscape(account)
+    req, err := client.NewRequest("GET", urlStr, nil)
+    if log.ErrorValue(err) == nil {
+        buffer := jira.User{}
+        _, err = client.Do(req, &buffer)
+        if log.ErrorValue(err) == nil {
+            result = &buffer
+        }
+    }
+    return
+}
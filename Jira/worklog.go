@@ -0,0 +1,155 @@
+// Jira/worklog.go
+//
+// Time logged against Jira issues.
+
+package Jira
+
+import (
+	"fmt"
+	"strings"
+
+	"lib.virginia.edu/agita/log"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A record of time logged against an issue.
+type Worklog struct {
+    Author  string  `json:"author"`             // Jira account of the user logging the time.
+    Started string  `json:"started"`            // Jira timestamp of the start of the work.
+    Seconds int     `json:"timeSpentSeconds"`   // Time spent.
+    Comment string  `json:"comment,omitempty"`
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Render a duration in seconds in the style of Jira time tracking values
+// (e.g. "3h 30m").
+//  NOTE: days and weeks are not used since their length in hours depends on
+//  the time tracking configuration of the Jira instance.
+func DurationString(seconds int) string {
+    if seconds <= 0 {
+        return "0m"
+    }
+    minutes := (seconds + 59) / 60
+    res := []string{}
+    if hours := minutes / 60; hours > 0 {
+        res = append(res, fmt.Sprintf("%dh", hours))
+    }
+    if minutes %= 60; minutes > 0 {
+        res = append(res, fmt.Sprintf("%dm", minutes))
+    }
+    return strings.Join(res, " ")
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// Get all worklog records for the issue in the order given by Jira.
+//  NOTE: Jira is not queried if the issue has no time spent.
+//  NOTE: may return partial results on error
+func (i *Issue) Worklogs() []Worklog {
+    if (i.client == nil) || (i.client.ptr == nil) { panic(ERR_NIL_CLIENT) }
+    if (i.ptr    == nil) || (i.ptr.Key    == "")  { panic(ERR_NO_ISSUE) }
+    if i.TimeSpent() == 0 {
+        return []Worklog{}
+    }
+    items  := getWorklogs(i.client.ptr, i.ptr.Key)
+    result := make([]Worklog, 0, len(items))
+    for _, item := range items {
+        worklog := Worklog{Account(item.Author), "", item.TimeSpentSeconds, item.Comment}
+        if item.Started != nil {
+            worklog.Started = TimeString(*item.Started)
+        }
+        result = append(result, worklog)
+    }
+    return result
+}
+
+// Return the underlying TimeOriginalEstimate in seconds.
+func (i *Issue) OriginalEstimate() int {
+    if noFields(i) { return 0 }
+    return i.ptr.Fields.TimeOriginalEstimate
+}
+
+// Return the underlying TimeEstimate (the remaining estimate) in seconds.
+func (i *Issue) RemainingEstimate() int {
+    if noFields(i) { return 0 }
+    return i.ptr.Fields.TimeEstimate
+}
+
+// Return the underlying TimeSpent in seconds.
+func (i *Issue) TimeSpent() int {
+    if noFields(i) { return 0 }
+    return i.ptr.Fields.TimeSpent
+}
+
+// Return the underlying AggregateTimeOriginalEstimate in seconds, which
+// includes the estimates of sub-tasks.
+func (i *Issue) AggregateOriginalEstimate() int {
+    if noFields(i) { return 0 }
+    return i.ptr.Fields.AggregateTimeOriginalEstimate
+}
+
+// Return the underlying AggregateTimeEstimate in seconds, which includes the
+// remaining estimates of sub-tasks.
+func (i *Issue) AggregateRemainingEstimate() int {
+    if noFields(i) { return 0 }
+    return i.ptr.Fields.AggregateTimeEstimate
+}
+
+// Return the underlying AggregateTimeSpent in seconds, which includes the time
+// spent on sub-tasks.
+func (i *Issue) AggregateTimeSpent() int {
+    if noFields(i) { return 0 }
+    return i.ptr.Fields.AggregateTimeSpent
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Get all worklog records for the indicated issue, requesting successive pages
+// until all have been received.
+//  NOTE: may return partial results on error
+func getWorklogs(client *jira.Client, issue IssueKey) []jira.WorklogRecord {
+    result := []jira.WorklogRecord{}
+    for {
+        urlStr   := fmt.Sprintf("rest/api/2/issue/%s/worklog?startAt=%d", issue, len(result))
+        req, err := client.NewRequest("GET", urlStr, nil)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        buffer := jira.Worklog{}
+        _, err = client.Do(req, &buffer)
+        if log.ErrorValue(err) != nil {
+            break
+        }
+        result = append(result, buffer.Worklogs...)
+        if (len(buffer.Worklogs) == 0) || (len(result) >= buffer.Total) {
+            break
+        }
+    }
+    return result
+}
+
+// Render worklog records as "author date duration" strings.
+func worklogStrings(worklog *jira.Worklog) []string {
+    if worklog == nil { return []string{} }
+    res := make([]string, 0, len(worklog.Worklogs))
+    for _, item := range worklog.Worklogs {
+        started := NO_TIME
+        if item.Started != nil {
+            started = TimeString(*item.Started)
+        }
+        res = append(res, fmt.Sprintf("%s %s %s", UserLabel(item.Author), started, DurationString(item.TimeSpentSeconds)))
+    }
+    return res
+}
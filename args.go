@@ -23,6 +23,10 @@ const (
     ModeExport   = 1 << iota
     ModeClear    = 1 << iota
     ModeTrial    = 1 << iota
+    ModePlan     = 1 << iota
+    ModeSync     = 1 << iota
+    ModeValidate = 1 << iota
+    ModeDiscover = 1 << iota
     ModeHelp     = 1 << iota
 )
 
@@ -56,6 +60,10 @@ func GetArgs() {
     export := flag.Bool("export",   false, "Generate JSON from Jira projects, issues, and comments.")
     clear  := flag.Bool("clear",    false, "Remove GitHub issues and comments.")
     trial  := flag.Bool("trial",    false, "Exercise Jira and GitHub APIs; see below.")
+    plan   := flag.Bool("plan",     false, "Write a transfer plan without updating GitHub.")
+    sync   := flag.Bool("sync",     false, "Update GitHub issues and comments from Jira changes since the last run.")
+    valid  := flag.Bool("validate", false, "Check the user directory against Jira projects and GitHub.")
+    disc   := flag.Bool("discover", false, "Propose user directory entries for Jira users.")
     help   := flag.Bool("help",     false, "Show program usage help.")
 
     flag.Usage = showUsage
@@ -74,6 +82,10 @@ func GetArgs() {
     if *export { mode = mode | ModeExport }
     if *clear  { mode = mode | ModeClear }
     if *trial  { mode = mode | ModeTrial }
+    if *plan   { mode = mode | ModePlan }
+    if *sync   { mode = mode | ModeSync }
+    if *valid  { mode = mode | ModeValidate }
+    if *disc   { mode = mode | ModeDiscover }
     if *help   { mode = mode | ModeHelp }
     if mode != ModeNone {
         Mode = mode
@@ -84,6 +96,10 @@ func GetArgs() {
         case ModeExport:    // ok
         case ModeClear:     // ok
         case ModeTrial:     // ok
+        case ModePlan:      // ok
+        case ModeSync:      // ok
+        case ModeValidate:  // ok
+        case ModeDiscover:  // ok
         case ModeHelp:      usage(NORMAL_EXIT)
         case ModeNone:      abort("no default mode defined")
         default:            abort("only one mode flag is acceptable")
@@ -115,6 +131,10 @@ func showUsage() {
     Show("Usage: %s -export   %s | Jira_projects...", prog, ALL_PROJECTS)
     Show("Usage: %s -clear    %s | GitHub_repos...",  prog, ALL_REPOS)
     Show("Usage: %s -trial    [args...]", prog)
+    Show("Usage: %s -plan     %s | Jira_projects...", prog, ALL_PROJECTS)
+    Show("Usage: %s -sync     %s | Jira_projects...", prog, ALL_PROJECTS)
+    Show("Usage: %s -validate %s | Jira_projects...", prog, ALL_PROJECTS)
+    Show("Usage: %s -discover %s | Jira_projects...", prog, ALL_PROJECTS)
     Show("Usage: %s -help", prog)
     Show("")
     Show("Mode Flags:")
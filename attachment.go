@@ -0,0 +1,482 @@
+// attachment.go
+//
+// Jira attachments stored for a GitHub repository by the backends in
+// storage.go.
+//
+// Attachments are streamed from Jira to staging files in ATTACH_CACHE_DIR and
+// from there to GitHub, so that large attachments are never held in memory.
+//
+// A file attached to several Jira issues is stored only once: the first copy
+// is stored as "KEY-filename" and references from later issues are pointed at
+// it.
+//
+// With ATTACH_MIRROR_IMAGES set, external images embedded in Jira issues and
+// comments are also copied into attachment storage (as "KEY-image-HASH.ext")
+// so that they do not depend on the original site.
+//
+// ATTACH_MANIFEST in the ATTACH_DIR of the repository describes every
+// stored file, including where it is stored, along with the Jira attachments
+// whose content it holds.
+
+package main
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+// Name of the file in ATTACH_DIR which describes the stored attachments.
+const ATTACH_MANIFEST = "MANIFEST.json"
+
+// Path relative to project root of the directory where downloaded Jira
+// attachments are staged, named by Jira attachment ID.
+const ATTACH_CACHE_DIR = "tmp/attachments"
+
+// If true, staged attachments are kept so that a later run need not download
+// them again; otherwise each is removed once it has been stored on GitHub.
+const ATTACH_CACHE = true
+
+// If true, external images are copied into attachment storage and embeds of
+// them refer to the copies.
+const ATTACH_MIRROR_IMAGES = false
+
+// Time allowed for downloading an external image.
+const MIRROR_TIMEOUT = 2 * time.Minute
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// The stored attachment files of a GitHub repository.
+type Manifest struct {
+    Files   []*ManifestFile             `json:"files"`
+    repo    string
+    byHash  map[string]*ManifestFile
+    pending map[string]bool             // Files batched but not yet stored.
+}
+
+// A stored attachment file.
+type ManifestFile struct {
+    File    string                      `json:"file"`
+    Store   string                      `json:"store"`     // Backend name.
+    Url     string                      `json:"url"`       // Relative to a GitHub issue.
+    Size    int64                       `json:"size"`
+    SHA256  string                      `json:"sha256"`
+    Issues  []string                    `json:"issues"`    // Jira issues which refer to the file.
+    Sources []*AttachmentSource         `json:"sources"`   // Jira attachments with the same content.
+}
+
+// The Jira attachment (or mirrored external image) from which a stored file
+// was copied.
+type AttachmentSource struct {
+    ID       string                     `json:"id"`        // Jira attachment ID or image URL.
+    Issue    string                     `json:"issue"`
+    Filename string                     `json:"filename"`
+    Author   string                     `json:"author,omitempty"`
+    Created  string                     `json:"created,omitempty"`
+    MimeType string                     `json:"mime_type,omitempty"`
+}
+
+// ============================================================================
+// Variables
+// ============================================================================
+
+// The client for downloading external images.
+var mirrorClient = &http.Client{Timeout: MIRROR_TIMEOUT}
+
+// The manifest of each repository which has been accessed in this run.
+//  NOTE: only accessed from the GitHub writer stage.
+var manifests = map[string]*Manifest{}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Get the manifest of stored attachments for the project repository of a Jira
+// project, fetching it from the backend selected by ManifestStoreFor() on
+// first use.
+//  NOTE: must be run in the GitHub writer stage.
+//  NOTE: never returns nil
+func RepoManifest(repo, proj string) *Manifest {
+    if manifest := manifests[repo]; manifest != nil {
+        return manifest
+    }
+    var content string
+    var found bool
+    if local, ok := ManifestStoreFor(proj).(localStore); ok {
+        content, found = local.read(repo, ATTACH_MANIFEST)
+    } else {
+        content, found = Github.GetProjAttachment(nil, repo, ATTACH_MANIFEST)
+    }
+    manifest := &Manifest{Files: []*ManifestFile{}}
+    if found && (json.Unmarshal([]byte(content), manifest) != nil) {
+        logError("%s: INVALID %s/%s", repo, Github.ATTACH_DIR, ATTACH_MANIFEST)
+        manifest.Files = []*ManifestFile{}
+    }
+    manifest.repo    = repo
+    manifest.byHash  = map[string]*ManifestFile{}
+    manifest.pending = map[string]bool{}
+    for _, file := range manifest.Files {
+        if file.Store == "" {
+            file.Store = STORE_REPO // From before there were other backends.
+        }
+        if store := AttachmentStoreNamed(file.Store); (file.Url == "") && (store != nil) {
+            file.Url = store.Url(file.File)
+        }
+        manifest.byHash[file.SHA256] = file
+    }
+    manifests[repo] = manifest
+    return manifest
+}
+
+// Forget the manifest of the repository so that it is fetched again from
+// GitHub on next use.
+//  NOTE: must be run in the GitHub writer stage.
+func DiscardManifest(repo string) {
+    delete(manifests, repo)
+}
+
+// ============================================================================
+// Methods
+// ============================================================================
+
+// Record a downloaded attachment in the manifest.  Unless `dedupe` is false,
+// an attachment whose content is already stored refers to the stored copy.
+//  NOTE: returns the file which holds the attachment content and whether the
+//  content must be stored.
+//  NOTE: a file which must be stored is pending until Stored() is called for
+//  it; content which is only pending is not deduplicated since the file may
+//  yet fail to be stored.
+func (m *Manifest) Add(attach *PreparedAttachment, dedupe bool) (*ManifestFile, bool) {
+    hash := attach.SHA256
+    file := m.byHash[hash]
+    if dedupe && (file != nil) && !m.pending[file.File] && !file.hasSource(attach.Source.ID) {
+        file.Sources = append(file.Sources, attach.Source)
+        if !slices.Contains(file.Issues, attach.Source.Issue) {
+            file.Issues = append(file.Issues, attach.Source.Issue)
+        }
+        return file, false
+    }
+
+    // The content is stored under its own name.
+    file = m.get(attach.File)
+    if file == nil {
+        file = &ManifestFile{File: attach.File}
+        m.Files = append(m.Files, file)
+    }
+    if !file.hasSource(attach.Source.ID) {
+        file.Sources = append(file.Sources, attach.Source)
+    }
+    if !slices.Contains(file.Issues, attach.Source.Issue) {
+        file.Issues = append(file.Issues, attach.Source.Issue)
+    }
+    file.Store  = attach.Store.Name()
+    file.Url    = attach.Store.Url(file.File)
+    file.Size   = attach.Size
+    file.SHA256 = hash
+    if _, found := m.byHash[hash]; !found {
+        m.byHash[hash] = file
+    }
+    m.pending[file.File] = true
+    return file, true
+}
+
+// Record that the named pending files have been stored, so that later
+// attachments with the same content may refer to them.
+func (m *Manifest) Stored(names ...string) {
+    for _, name := range names {
+        delete(m.pending, name)
+    }
+}
+
+// Remove the named files, which could not be stored, from the manifest.
+func (m *Manifest) Remove(names ...string) {
+    for _, name := range names {
+        delete(m.pending, name)
+        if file := m.get(name); file != nil {
+            m.Files = slices.DeleteFunc(m.Files, func(f *ManifestFile) bool { return f == file })
+            if m.byHash[file.SHA256] == file {
+                delete(m.byHash, file.SHA256)
+            }
+        }
+    }
+}
+
+// The replacement of the URL of each attachment of the Jira issue which refers
+// to a copy stored under another name.
+func (m *Manifest) Renames(key string) map[string]string {
+    renames := map[string]string{}
+    for _, file := range m.Files {
+        for _, src := range file.Sources {
+            if src.Issue != key {
+                continue
+            }
+            name := convert.AttachmentFile(key, src.Filename)
+            if strings.Contains(src.ID, "://") {
+                name = mirrorFile(key, src.ID) // A mirrored image.
+            }
+            if name == file.File {
+                continue
+            }
+            store, err := AttachmentStoreFor(m.repo, key, name, file.Size)
+            if err != nil {
+                continue
+            }
+            if url := store.Url(name); url != file.Url {
+                renames[url] = file.Url
+            }
+        }
+    }
+    return renames
+}
+
+// Render the manifest as JSON.
+func (m *Manifest) Json() string {
+    bytes, err := json.MarshalIndent(m, "", "  ")
+    if err != nil {
+        logError("%s: %s: %v", m.repo, ATTACH_MANIFEST, err)
+    }
+    return string(bytes)
+}
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// The manifest entry for the named file.
+//  NOTE: returns nil if there is no such file.
+func (m *Manifest) get(name string) *ManifestFile {
+    for _, file := range m.Files {
+        if file.File == name {
+            return file
+        }
+    }
+    return nil
+}
+
+// Indicate whether the file holds the content of the given Jira attachment.
+func (f *ManifestFile) hasSource(id string) bool {
+    return slices.ContainsFunc(f.Sources, func(src *AttachmentSource) bool {
+        return src.ID == id
+    })
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Describe a Jira attachment for the manifest.
+func attachmentSource(key string, attach *Jira.Attachment) *AttachmentSource {
+    return &AttachmentSource{
+        ID:       attach.ID,
+        Issue:    key,
+        Filename: attach.Filename,
+        Author:   Jira.Account(attach.Author),
+        Created:  attach.Created,
+        MimeType: attach.MimeType,
+    }
+}
+
+// Point links to attachments at the stored copies, given the replacement of
+// each attachment URL.
+//  NOTE: URLs are matched along with the end of the Markdown link or HTML
+//  attribute which contains them.
+func renameAttachments(text string, renames map[string]string) string {
+    for from, to := range renames {
+        text = strings.ReplaceAll(text, "(" + from + ")", "(" + to + ")")
+        text = strings.ReplaceAll(text, `"` + html.EscapeString(from) + `"`, `"` + html.EscapeString(to) + `"`)
+    }
+    return text
+}
+
+// Download a Jira attachment to a file in ATTACH_CACHE_DIR, unless a copy of
+// the right size was staged by an earlier run.
+//  NOTE: the content is streamed to the file and is never held in memory.
+func stageAttachment(key string, attach *Jira.Attachment) (*PreparedAttachment, error) {
+    dir := filepath.Join(util.RootPath(), ATTACH_CACHE_DIR)
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return nil, err
+    }
+    prep := &PreparedAttachment{
+        File:   convert.AttachmentFile(key, attach.Filename),
+        Path:   filepath.Join(dir, attach.ID),
+        Source: attachmentSource(key, attach),
+    }
+    if info, err := os.Stat(prep.Path); (err == nil) && (info.Size() == int64(attach.Size)) {
+        prep.Size, prep.SHA256, err = fileHash(prep.Path)
+        return prep, err
+    }
+
+    body, err := Jira.OpenAttachment(nil, attach.ID)
+    if err != nil {
+        return nil, err
+    }
+    defer body.Close()
+    prep.Size, prep.SHA256, err = stageContent(prep.Path, body, int64(attach.Size))
+    if err != nil {
+        return nil, err
+    }
+    return prep, nil
+}
+
+// Copy the external images embedded in a converted issue and its comments into
+// attachment storage, pointing the embeds at the copies.
+//  NOTE: an image which cannot be downloaded or stored is left as a link to
+//  its URL.
+//  NOTE: returns the images which the ledger does not show as stored.
+func mirrorImages(prep *PreparedIssue, lg *ledger.Ledger) []*PreparedAttachment {
+    entry := lg.Get(prep.Key)
+    texts := []*string{&prep.Issue.Body}
+    for _, comment := range prep.Comments {
+        texts = append(texts, &comment.Body)
+    }
+    res     := []*PreparedAttachment{}
+    renames := map[string]string{}
+    for _, text := range texts {
+        for _, link := range convert.ExternalImages(*text) {
+            if _, done := renames[link]; done {
+                continue
+            }
+            renames[link] = link
+            image, err := stageImage(prep.Key, link)
+            if err == nil {
+                image.Store, err = AttachmentStoreFor(lg.Repo, prep.Key, image.File, image.Size)
+            }
+            if err != nil {
+                logWarning("%s: IMAGE %q NOT MIRRORED: %v", prep.Key, link, err)
+                continue
+            }
+            renames[link] = image.Store.Url(image.File)
+            if !entry.HasAttachment(image.File) {
+                res = append(res, image)
+            }
+        }
+        *text = renameAttachments(*text, renames)
+    }
+    return res
+}
+
+// The name of the stored copy of an external image embedded in a Jira issue.
+func mirrorFile(key, link string) string {
+    sum := sha1.Sum([]byte(link))
+    ext := ""
+    if parsed, err := url.Parse(link); err == nil {
+        ext = strings.ToLower(path.Ext(parsed.Path))
+    }
+    if !convert.IsImage(ext) {
+        ext = ""
+    }
+    return convert.AttachmentFile(key, "image-" + hex.EncodeToString(sum[:4]) + ext)
+}
+
+// Download an external image to a file in ATTACH_CACHE_DIR, unless it was
+// staged by an earlier run.
+func stageImage(key, link string) (*PreparedAttachment, error) {
+    dir := filepath.Join(util.RootPath(), ATTACH_CACHE_DIR)
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return nil, err
+    }
+    sum  := sha1.Sum([]byte(link))
+    name := link
+    if parsed, err := url.Parse(link); err == nil {
+        name = path.Base(parsed.Path)
+    }
+    prep := &PreparedAttachment{
+        File:   mirrorFile(key, link),
+        Path:   filepath.Join(dir, "image-" + hex.EncodeToString(sum[:])),
+        Source: &AttachmentSource{ID: link, Issue: key, Filename: name},
+    }
+    if _, err := os.Stat(prep.Path); err == nil {
+        prep.Size, prep.SHA256, err = fileHash(prep.Path)
+        return prep, err
+    }
+    rsp, err := mirrorClient.Get(link)
+    if err != nil {
+        return nil, err
+    }
+    defer rsp.Body.Close()
+    kind := rsp.Header.Get("Content-Type")
+    switch {
+        case rsp.StatusCode != http.StatusOK:
+            return nil, fmt.Errorf("%s", rsp.Status)
+        case !strings.HasPrefix(kind, "image/"):
+            return nil, fmt.Errorf("content type %q is not an image", kind)
+    }
+    prep.Source.MimeType = kind
+    prep.Size, prep.SHA256, err = stageContent(prep.Path, rsp.Body, -1)
+    if err != nil {
+        return nil, err
+    }
+    return prep, nil
+}
+
+// Stream content to a staging file through a temporary file which replaces it
+// when done.
+//  NOTE: returns the size and SHA-256 hash (as a hex string) of the content.
+//  NOTE: unless `expect` is negative, content of another size is an error.
+func stageContent(path string, src io.Reader, expect int64) (int64, string, error) {
+    temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path) + ".*")
+    if err != nil {
+        return 0, "", err
+    }
+    hash := sha256.New()
+    size, err := io.Copy(io.MultiWriter(temp, hash), src)
+    if closeErr := temp.Close(); err == nil {
+        err = closeErr
+    }
+    if (err == nil) && (expect >= 0) && (size != expect) {
+        err = fmt.Errorf("downloaded %d bytes; expected %d", size, expect)
+    }
+    if err == nil {
+        err = os.Rename(temp.Name(), path)
+    }
+    if err != nil {
+        os.Remove(temp.Name())
+        return 0, "", err
+    }
+    return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// The size and SHA-256 hash (as a hex string) of the content of a file.
+func fileHash(path string) (int64, string, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return 0, "", err
+    }
+    defer file.Close()
+    return readerHash(file)
+}
+
+// The size and SHA-256 hash (as a hex string) of the content of a reader.
+func readerHash(src io.Reader) (int64, string, error) {
+    hash := sha256.New()
+    size, err := io.Copy(hash, src)
+    if err != nil {
+        return 0, "", err
+    }
+    return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
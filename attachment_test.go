@@ -0,0 +1,162 @@
+// attachment_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/convert"
+)
+
+// ============================================================================
+// Tests - Methods
+// ============================================================================
+
+func TestManifestAdd(t *testing.T) {
+    const fn = "Manifest.Add"
+
+    m := testManifest()
+
+    // New content is stored under its own name.
+    a := testAttachment("PROJ-1", "10001", "shot.png", "hash-a")
+    file, store := m.Add(a, true)
+    if !store || (file.File != a.File) {
+        t.Fatalf("%s(new) = (%q, %v), want (%q, true)", fn, file.File, store, a.File)
+    }
+
+    // Content which is only pending is not shared.
+    b := testAttachment("PROJ-2", "10002", "copy.png", "hash-a")
+    if file, store := m.Add(b, true); !store || (file.File != b.File) {
+        t.Errorf("%s(pending hash) = (%q, %v), want (%q, true)", fn, file.File, store, b.File)
+    }
+
+    // Once stored, the same content is shared.
+    m.Stored(a.File, b.File)
+    c := testAttachment("PROJ-3", "10003", "again.png", "hash-a")
+    if file, store := m.Add(c, true); store || (file.File != a.File) {
+        t.Errorf("%s(stored hash) = (%q, %v), want (%q, false)", fn, file.File, store, a.File)
+    }
+    if got := m.get(a.File).Issues; !slices.Equal(got, []string{"PROJ-1", "PROJ-3"}) {
+        t.Errorf("%s() issues = %q, want PROJ-1 and PROJ-3", fn, got)
+    }
+
+    // Without deduplication the content is stored again under its own name.
+    d := testAttachment("PROJ-4", "10004", "d.png", "hash-a")
+    if file, store := m.Add(d, false); !store || (file.File != d.File) {
+        t.Errorf("%s(dedupe=false) = (%q, %v), want (%q, true)", fn, file.File, store, d.File)
+    }
+
+    // The same source added twice is recorded once.
+    m.Stored(d.File)
+    m.Add(a, true)
+    if got := len(m.get(a.File).Sources); got != 2 {
+        t.Errorf("%s(same source) sources = %d, want 2", fn, got)
+    }
+    if got := len(m.Files); got != 3 {
+        t.Errorf("%s() files = %d, want 3", fn, got)
+    }
+}
+
+func TestManifestRemove(t *testing.T) {
+    const fn = "Manifest.Remove"
+
+    m := testManifest()
+    a := testAttachment("PROJ-1", "10001", "shot.png", "hash-a")
+    m.Add(a, true)
+    m.Stored(a.File)
+    b := testAttachment("PROJ-2", "10002", "copy.png", "hash-a")
+    m.Add(b, true)
+
+    // Removing the deduplicated target leaves no file with its content.
+    m.Remove(a.File)
+    if m.get(a.File) != nil {
+        t.Errorf("%s() left %q", fn, a.File)
+    }
+    if got := m.byHash["hash-a"]; got != nil {
+        t.Errorf("%s() left hash for %q", fn, got.File)
+    }
+    c := testAttachment("PROJ-3", "10003", "again.png", "hash-a")
+    if file, store := m.Add(c, true); !store || (file.File != c.File) {
+        t.Errorf("%s() then Add = (%q, %v), want (%q, true)", fn, file.File, store, c.File)
+    }
+    m.Remove("missing.png")
+    if got := len(m.Files); got != 1 {
+        t.Errorf("%s() files = %d, want 1", fn, got)
+    }
+}
+
+func TestManifestRenames(t *testing.T) {
+    const fn = "Manifest.Renames"
+
+    m := testManifest()
+    a := testAttachment("PROJ-1", "10001", "shot.png", "hash-a")
+    m.Add(a, true)
+    m.Stored(a.File)
+    b := testAttachment("PROJ-2", "10002", "copy.png", "hash-a")
+    m.Add(b, true)
+
+    want := map[string]string{
+        convert.AttachmentUrl(b.File): convert.AttachmentUrl(a.File),
+    }
+    if got := m.Renames("PROJ-2"); !maps.Equal(got, want) {
+        t.Errorf("%s(PROJ-2) = %v, want %v", fn, got, want)
+    }
+    if got := m.Renames("PROJ-1"); len(got) != 0 {
+        t.Errorf("%s(PROJ-1) = %v, want none", fn, got)
+    }
+}
+
+func TestManifestJson(t *testing.T) {
+    const fn = "Manifest.Json"
+
+    m := testManifest()
+    m.Add(testAttachment("PROJ-1", "10001", "shot.png", "hash-a"), true)
+    m.Add(testAttachment("PROJ-2", "10002", "notes.pdf", "hash-b"), true)
+
+    got := &Manifest{}
+    if err := json.Unmarshal([]byte(m.Json()), got); err != nil {
+        t.Fatalf("%s() = invalid JSON: %v", fn, err)
+    }
+    if len(got.Files) != len(m.Files) {
+        t.Fatalf("%s() files = %d, want %d", fn, len(got.Files), len(m.Files))
+    }
+    for idx, want := range m.Files {
+        g := got.Files[idx]
+        same := (g.File == want.File) && (g.Store == want.Store) && (g.Url == want.Url) &&
+            (g.Size == want.Size) && (g.SHA256 == want.SHA256) &&
+            slices.Equal(g.Issues, want.Issues) &&
+            (len(g.Sources) == 1) && (*g.Sources[0] == *want.Sources[0])
+        if !same {
+            t.Errorf("%s() file %d = %+v, want %+v", fn, idx, *g, *want)
+        }
+    }
+}
+
+// ============================================================================
+// Internal functions - test support
+// ============================================================================
+
+// An empty manifest which is not fetched from GitHub.
+func testManifest() *Manifest {
+    return &Manifest{
+        Files:   []*ManifestFile{},
+        repo:    "test-manifest-repo",
+        byHash:  map[string]*ManifestFile{},
+        pending: map[string]bool{},
+    }
+}
+
+// A downloaded attachment to be stored in the project repository.
+func testAttachment(key, id, name, hash string) *PreparedAttachment {
+    return &PreparedAttachment{
+        File:   convert.AttachmentFile(key, name),
+        Size:   1024,
+        SHA256: hash,
+        Source: &AttachmentSource{ID: id, Issue: key, Filename: name},
+        Store:  repoStore{},
+    }
+}
+
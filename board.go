@@ -0,0 +1,245 @@
+// board.go
+//
+// GitHub projects (Projects v2) for Jira Agile boards.
+//
+// After the issues of a Jira project have been transferred, an organization
+// project linked to the target repository is created for each Jira board which
+// shows issues of the Jira project; a project with the same title which already
+// exists is used instead.
+//
+// * The options of the project's "Status" field are replaced by the columns of
+//   the board.
+// * The sprints of a scrum board become the iterations of a "Sprint" field.
+// * Jira custom fields mapped to project fields by convert.FieldMap become text,
+//   number or date fields of the project.
+// * Each transferred issue on the board is added to the project in rank order
+//   with the column of its Jira status, the iteration of its latest sprint and
+//   the values of its mapped custom fields.
+//
+// The items already on the project are read first so that a repeated transfer
+// or sync only adds missing issues, moves issues whose rank has changed and
+// sets fields whose values differ.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"lib.virginia.edu/agita/convert"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Create or update the GitHub projects for the boards of the Jira project.
+//  NOTE: returns the number of issues placed on GitHub projects.
+func prepareBoards(project *Jira.Project, repo string) (count int) {
+    if FakeTransfer { return }
+    for _, board := range project.Boards(convert.ProjectFieldSources()...) {
+        count += prepareBoard(project, board, repo)
+    }
+    return
+}
+
+// Create or update the GitHub project for a Jira board.
+//  NOTE: returns the number of issues placed on the GitHub project.
+func prepareBoard(project *Jira.Project, board Jira.Board, repo string) (count int) {
+    title := convert.BoardTitle(board)
+    var proj   *Github.ProjectV2
+    var fields map[string]Github.ProjectField
+    githubWriter.Do(func() {
+        proj, fields = prepareBoardProject(project, board, repo)
+    })
+    if proj == nil {
+        return
+    }
+    status := fields[convert.BOARD_STATUS_FIELD]
+    sprint := fields[convert.BOARD_SPRINT_FIELD]
+
+    // Get the items already on the project and their order.
+    var items []Github.ProjectItem
+    githubWriter.Do(func() {
+        items = Github.GetProjectItems(proj)
+    })
+    if items == nil {
+        logError("%s: PROJECT %q ITEMS NOT READ", project.Key(), title)
+        return
+    }
+    existing := map[string]Github.ProjectItem{}
+    order    := make([]string, 0, len(items))
+    for _, item := range items {
+        if item.Repo != "" {
+            existing[itemKey(item.Repo, item.Number)] = item
+        }
+        order = append(order, item.ID)
+    }
+
+    // Add or move transferred issues in rank order, setting only the fields
+    // whose values have changed.
+    client := Github.MainClient()
+    after  := ""
+    for _, key := range board.Issues {
+        other, entry := transferredIssue(key)
+        if entry == nil {
+            continue
+        }
+        githubWriter.Do(func() {
+            item, found := existing[itemKey(other, entry.Issue)]
+            if !found {
+                if item.ID = Github.AddProjectItem(client, proj, Github.ORG, other, entry.Issue); item.ID == "" {
+                    logError("%s: NOT ADDED TO PROJECT %q", key, title)
+                    return
+                }
+                order = append(order, item.ID)
+            }
+            if idx := slices.Index(order, item.ID); previousItem(order, idx) != after {
+                if !Github.MoveProjectItem(proj, item.ID, after) {
+                    logError("%s: PROJECT %q ITEM NOT MOVED", key, title)
+                } else {
+                    order = slices.Delete(order, idx, idx+1)
+                    order = slices.Insert(order, slices.Index(order, after)+1, item.ID)
+                }
+            }
+            if column := board.Column[key]; (column != "") && (column != item.Values[status.Name]) {
+                if !Github.SetProjectItemField(proj, item.ID, status, column, false) {
+                    logError("%s: PROJECT %q STATUS %q NOT SET", key, title, column)
+                }
+            }
+            if name := convert.SprintName(board, board.Sprint[key]); (name != "") && (name != item.Values[sprint.Name]) {
+                if !Github.SetProjectItemField(proj, item.ID, sprint, name, true) {
+                    logError("%s: PROJECT %q SPRINT %q NOT SET", key, title, name)
+                }
+            }
+            for _, value := range convert.FieldValues(board.Values[key], convert.FIELD_PROJECT) {
+                if sameValue(value.Type, item.Values[value.Name], value.Value) {
+                    continue
+                }
+                field, found := fields[value.Name]
+                if !found || !Github.SetProjectItemValue(proj, item.ID, field, value.Type, value.Value) {
+                    logError("%s: PROJECT %q FIELD %q NOT SET", key, title, value.Name)
+                }
+            }
+            after = item.ID
+            count++
+        })
+    }
+    return
+}
+
+// Get or create the GitHub project for a Jira board and set up its fields.
+//  NOTE: returns nil if the project could not be created.
+func prepareBoardProject(project *Jira.Project, board Jira.Board, repo string) (*Github.ProjectV2, map[string]Github.ProjectField) {
+    title := convert.BoardTitle(board)
+    proj  := Github.GetProjectV2(Github.ORG, title)
+    if proj == nil {
+        if proj = Github.CreateProjectV2(Github.ORG, repo, title); proj == nil {
+            logError("%s: PROJECT %q NOT CREATED", project.Key(), title)
+            return nil, nil
+        }
+    }
+    fields := Github.GetProjectFields(proj)
+
+    // Replace the default status options with the board columns.
+    columns := convert.BoardColumns(board)
+    status, found := fields[convert.BOARD_STATUS_FIELD]
+    if found && (len(columns) > 0) && !sameOptions(status, columns) {
+        if !Github.SetFieldOptions(status, columns) {
+            logError("%s: PROJECT %q COLUMNS NOT SET", project.Key(), title)
+        }
+        fields = Github.GetProjectFields(proj)
+    }
+
+    // Create the sprint field from the board sprints, or add the sprints
+    // which were created in Jira after the field was created.
+    sprints := convert.BoardSprints(board)
+    sprint, found := fields[convert.BOARD_SPRINT_FIELD]
+    switch {
+        case len(sprints) == 0:
+            // No sprint field is needed.
+        case !found:
+            if !Github.CreateIterationField(proj, convert.BOARD_SPRINT_FIELD, sprints) {
+                logError("%s: PROJECT %q SPRINTS NOT SET", project.Key(), title)
+            }
+            fields = Github.GetProjectFields(proj)
+        case !hasIterations(sprint, sprints):
+            if !Github.SetFieldIterations(sprint, sprints) {
+                logError("%s: PROJECT %q SPRINTS NOT UPDATED", project.Key(), title)
+            }
+            fields = Github.GetProjectFields(proj)
+    }
+
+    // Create fields for custom fields mapped to the project.
+    created := false
+    for name, dataType := range convert.ProjectFields() {
+        if _, found := fields[name]; !found {
+            if !Github.CreateProjectField(proj, name, dataType) {
+                logError("%s: PROJECT %q FIELD %q NOT CREATED", project.Key(), title, name)
+            }
+            created = true
+        }
+    }
+    if created {
+        fields = Github.GetProjectFields(proj)
+    }
+    return proj, fields
+}
+
+// The key of a project item for the given repository issue.
+func itemKey(repo string, number int) string {
+    return fmt.Sprintf("%s#%d", repo, number)
+}
+
+// The project item preceding the given position in the project order (blank
+// if it is at the top).
+func previousItem(order []string, idx int) string {
+    if idx <= 0 {
+        return ""
+    }
+    return order[idx-1]
+}
+
+// Indicate whether a project item field value already matches the value to be
+// set, where `dataType` is "text", "number" or "date" (default "text").
+func sameValue(dataType, have, want string) bool {
+    if have == "" {
+        return false
+    }
+    switch strings.ToLower(dataType) {
+        case "number":
+            h, err1 := strconv.ParseFloat(have, 64)
+            w, err2 := strconv.ParseFloat(want, 64)
+            return (err1 == nil) && (err2 == nil) && (h == w)
+        case "date":
+            size := len(time.DateOnly)
+            return have[:min(len(have), size)] == want[:min(len(want), size)]
+        default:
+            return have == want
+    }
+}
+
+// Indicate whether the iteration field has all of the given iterations.
+func hasIterations(field Github.ProjectField, iterations []Github.Iteration) bool {
+    return !slices.ContainsFunc(iterations, func(iter Github.Iteration) bool {
+        _, found := field.Options[iter.Title]
+        return !found
+    })
+}
+
+// Indicate whether the single-select field has exactly the given options.
+func sameOptions(field Github.ProjectField, options []Github.FieldOption) bool {
+    if len(field.Options) != len(options) {
+        return false
+    }
+    return !slices.ContainsFunc(options, func(opt Github.FieldOption) bool {
+        _, found := field.Options[opt.Name]
+        return !found
+    })
+}
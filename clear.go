@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"slices"
 
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+
 	"lib.virginia.edu/agita/Github"
 )
 
@@ -16,6 +19,8 @@ import (
 // ============================================================================
 
 // Remove all GitHub repository issues and comments.
+// The transfer ledger of the associated Jira project is also discarded so that
+// a subsequent transfer will start from scratch.
 func ClearAll(names ...string) {
     names  = ValidateRepoNames(names...)
     count := 0
@@ -30,6 +35,9 @@ func ClearAll(names ...string) {
             } else {
                 fmt.Printf("Repository %q - %d issues removed\n", name, num)
             }
+            if proj := ledgerProject(name); (proj != "") && ledger.Remove(proj) {
+                fmt.Printf("Repository %q - %s transfer ledger removed\n", name, proj)
+            }
             count++
         }
     }
@@ -39,3 +47,26 @@ func ClearAll(names ...string) {
         fmt.Printf("%d repositories cleared\n", count)
     }
 }
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The Jira project whose issues were transferred to the repository.
+//  NOTE: returns blank unless the project ledger records the repository (or,
+//  for a ledger without a recorded repository, unless the repository is the
+//  current destination of the project).
+func ledgerProject(repo string) string {
+    proj := convert.ProjectFor(repo)
+    if (proj == "") || !ledger.Exists(proj) {
+        return ""
+    }
+    recorded := ledger.RecordedRepo(proj)
+    if recorded == "" {
+        recorded, _ = projectRepository(proj)
+    }
+    if recorded != repo {
+        return ""
+    }
+    return proj
+}
@@ -0,0 +1,277 @@
+// convert/attachment.go
+//
+// Conversion of references to Jira attachments into references to the copies
+// stored for GitHub.  Where each copy is stored is decided by the caller, which
+// supplies an AttachmentLocator giving the URL of the copy.
+
+package convert
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/re"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// Gives the relative URL from a GitHub issue of the stored copy of an
+// attachment of a Jira issue.
+//  NOTE: returns blank if the attachment cannot be stored.
+type AttachmentLocator func(issue Jira.IssueKey, attach *Jira.Attachment) string
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The heading of the section listing attachments in a GitHub issue body.
+const ATTACHMENTS_HEADING = "Attachments"
+
+// The width in pixels of an image shown as a Jira "thumbnail".
+const THUMBNAIL_WIDTH = 200
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// File extensions of attachments which can be shown as images.
+var IMAGE_EXTENSIONS = []string{
+    ".bmp", ".gif", ".jpeg", ".jpg", ".png", ".svg", ".webp",
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// The name of the stored copy of an attachment of a Jira issue.
+func AttachmentFile(issue Jira.IssueKey, name string) string {
+    if issue == "" {
+        return name
+    }
+    return issue + "-" + name
+}
+
+// The relative URL from a GitHub issue of an attachment file stored in the
+// ATTACH_DIR of its repository.
+func AttachmentUrl(file string) string {
+    return "../blob/main/" + Github.ATTACH_DIR + "/" + url.PathEscape(file) + "?raw=true"
+}
+
+// Replace Jira attachment references (after conversion to Markdown) with
+// references to the stored copies of the attachments of the issue.
+//
+// * An embedded image ("!name!") becomes a Markdown image or, if it has
+//   options like "thumbnail", "width=" or "align=", an HTML <img> tag.
+// * An embedded attachment which is not an image becomes a link.
+// * An attachment link ("[^name]" or "[text|^name]") becomes a link.
+// * An embedded image given by an http(s) URL ("!https://host/image.png!")
+//   becomes an image of the URL in the same way.
+//
+// Any other "!...!" text (e.g. "Done!  Really!") is not an embed unless it
+// names an attachment of the issue, and is left unchanged.  A reference to an
+// attachment which cannot be stored is left as its name.
+//
+func Attachments(text string, issue Jira.Issue, locate AttachmentLocator) string {
+    key  := issue.Key()
+    find := func(name string) string {
+        if attach := attachmentNamed(issue, name); attach != nil {
+            return locate(key, attach)
+        }
+        return locate(key, &Jira.Attachment{Filename: name})
+    }
+    embed := func(match string) string {
+        parts := re.FindAllStringSubmatch(match, `^!([^|!\s][^|!]*)(?:\|([^!]*))?!$`, 1)
+        if len(parts) == 0 {
+            return match
+        }
+        name, options := strings.TrimSpace(parts[0][1]), parts[0][2]
+        switch {
+            case IsExternalUrl(name) && (options == ""):
+                return fmt.Sprintf("![](%s)", name)
+            case IsExternalUrl(name):
+                return imageTag(path.Base(name), name, options)
+            case attachmentNamed(issue, name) == nil:
+                return match // Not an attachment.
+        }
+        link := find(name)
+        switch {
+            case link == "":
+                return name
+            case !IsImage(name):
+                return fmt.Sprintf("[%s](%s)", name, link)
+            case options == "":
+                return fmt.Sprintf("![%s](%s)", name, link)
+        }
+        return imageTag(name, link, options)
+    }
+    text = re.ReplaceAllFunc(text, `!\S[^!\n]+?!`, embed)
+    link := func(match string) string {
+        name := strings.TrimPrefix(strings.TrimSuffix(match, ">"), "<^")
+        if link := find(name); link != "" {
+            return fmt.Sprintf("[%s](%s)", name, link)
+        }
+        return name
+    }
+    text = re.ReplaceAllFunc(text, `<\^[^>\n]+>`, link)
+    target := func(match string) string {
+        label, name, _ := strings.Cut(strings.TrimSuffix(match, ")"), "](^")
+        if link := find(name); link != "" {
+            return label + "](" + link + ")"
+        }
+        return strings.TrimPrefix(label, "[")
+    }
+    return re.ReplaceAllFunc(text, `\[[^\]\n]*\]\(\^[^)\n]+\)`, target)
+}
+
+// Generate a Markdown section listing every attachment of the issue with a
+// link to its stored copy, its size, its type, and where it is referenced: the
+// description and/or the (1-based) numbers of the comments which embed or link
+// to it.
+//  NOTE: an attachment which cannot be stored is listed without a link.
+//  NOTE: returns blank if the issue has no attachments.
+func AttachmentList(issue Jira.Issue, comments []Jira.Comment, locate AttachmentLocator) string {
+    attachments := issue.Attachments()
+    if len(attachments) == 0 {
+        return ""
+    }
+    lines := []string{
+        "**" + ATTACHMENTS_HEADING + "**",
+        "",
+        "| File | Size | Type | Referenced in |",
+        "|------|------|------|---------------|",
+    }
+    for _, attach := range attachments {
+        name := strings.ReplaceAll(attach.Filename, "|", "\\|")
+        file := name + " (too large to store on GitHub)"
+        if link := locate(issue.Key(), attach); link != "" {
+            file = fmt.Sprintf("[%s](%s)", name, link)
+        }
+        kind := attach.MimeType
+        if kind == "" {
+            kind = "-"
+        }
+        refs := []string{}
+        if referencesAttachment(issue.Description(), attach.Filename) {
+            refs = append(refs, "description")
+        }
+        for idx, comment := range comments {
+            if referencesAttachment(comment.Body(), attach.Filename) {
+                refs = append(refs, fmt.Sprintf("comment %d", idx + 1))
+            }
+        }
+        if len(refs) == 0 {
+            refs = append(refs, "-")
+        }
+        lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s |", file, ByteSize(attach.Size), kind, strings.Join(refs, ", ")))
+    }
+    return strings.Join(lines, "\n")
+}
+
+// The URLs of the external images embedded in converted text (i.e. those not
+// stored as attachments), in order of first appearance.
+func ExternalImages(text string) []string {
+    res := []string{}
+    for _, parts := range re.FindAllStringSubmatch(text, `!\[[^\]\n]*\]\((https?://[^)\s]+)\)|<img src="(https?://[^"]+)"`, -1) {
+        link := parts[1]
+        if link == "" {
+            link = html.UnescapeString(parts[2])
+        }
+        if !slices.Contains(res, link) {
+            res = append(res, link)
+        }
+    }
+    return res
+}
+
+// Indicate whether the text is an http or https URL.
+func IsExternalUrl(text string) bool {
+    link, err := url.Parse(text)
+    return (err == nil) && ((link.Scheme == "http") || (link.Scheme == "https")) && (link.Host != "")
+}
+
+// Indicate whether the attachment file can be shown as an image.
+func IsImage(name string) bool {
+    return slices.Contains(IMAGE_EXTENSIONS, strings.ToLower(path.Ext(name)))
+}
+
+// Render a size in bytes for display (e.g. "512 B", "1.5 KB", "12.3 MB").
+func ByteSize(size int) string {
+    const unit = 1024
+    if size < unit {
+        return fmt.Sprintf("%d B", size)
+    }
+    value := float64(size) / unit
+    for _, prefix := range "KMG" {
+        if (value < unit) || (prefix == 'G') {
+            return fmt.Sprintf("%.1f %cB", value, prefix)
+        }
+        value /= unit
+    }
+    return ""
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Indicate whether Jira markup embeds ("!name!" or "!name|options!") or links
+// to ("[^name]" or "[text|^name]") the named attachment.
+func referencesAttachment(text, name string) bool {
+    return strings.Contains(text, "!" + name + "!") ||
+        strings.Contains(text, "!" + name + "|") ||
+        strings.Contains(text, "^" + name + "]")
+}
+
+// The attachment of the issue with the given file name.
+//  NOTE: returns nil if there is no such attachment.
+func attachmentNamed(issue Jira.Issue, name string) *Jira.Attachment {
+    for _, attach := range issue.Attachments() {
+        if attach.Filename == name {
+            return attach
+        }
+    }
+    return nil
+}
+
+// Generate an HTML <img> tag for an embedded Jira image with options, e.g.
+// "thumbnail" or "width=300,height=200,align=right,alt=Screen shot".
+//  NOTE: a thumbnail links to the full-size image as it does in Jira.
+func imageTag(name, link, options string) string {
+    attrs := map[string]string{"alt": name}
+    thumb := false
+    for _, option := range strings.Split(options, ",") {
+        key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+        key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+        switch key {
+            case "thumbnail":
+                thumb = true
+            case "width", "height", "align", "alt", "title", "hspace", "vspace", "border":
+                if value != "" {
+                    attrs[key] = value
+                }
+        }
+    }
+    if thumb && (attrs["width"] == "") && (attrs["height"] == "") {
+        attrs["width"] = fmt.Sprint(THUMBNAIL_WIDTH)
+    }
+    tag := fmt.Sprintf(`<img src="%s"`, html.EscapeString(link))
+    for _, key := range []string{"alt", "title", "width", "height", "align", "hspace", "vspace", "border"} {
+        if value := attrs[key]; value != "" {
+            tag += fmt.Sprintf(` %s="%s"`, key, html.EscapeString(value))
+        }
+    }
+    tag += ">"
+    if thumb {
+        tag = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), tag)
+    }
+    return tag
+}
@@ -0,0 +1,245 @@
+// convert/attachment_test.go
+
+package convert
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Jira"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestAttachments(t *testing.T) {
+    const fn = "Attachments"
+
+	type testCase struct {
+		name string
+		text string
+		want string
+	}
+
+    issue := testIssue("LIBRA-1", "screen shot.png", "notes.pdf", "huge.png")
+    locate := func(key Jira.IssueKey, attach *Jira.Attachment) string {
+        if attach.Filename == "huge.png" {
+            return ""
+        }
+        return "files/" + AttachmentFile(key, attach.Filename)
+    }
+    Case := func(idx int, text, want string) testCase {
+        return testCase{test.CaseName(fn, idx), text, want}
+    }
+
+	tests := []testCase{
+        Case(0,  "!screen shot.png!",
+                 "![screen shot.png](files/LIBRA-1-screen shot.png)"),
+        Case(1,  "!screen shot.png|thumbnail!",
+                 `<a href="files/LIBRA-1-screen shot.png"><img src="files/LIBRA-1-screen shot.png" alt="screen shot.png" width="200"></a>`),
+        Case(2,  "!screen shot.png|width=300, align=right!",
+                 `<img src="files/LIBRA-1-screen shot.png" alt="screen shot.png" width="300" align="right">`),
+        Case(3,  "!notes.pdf!",
+                 "[notes.pdf](files/LIBRA-1-notes.pdf)"),
+        Case(4,  "!huge.png!",
+                 "huge.png"),
+        Case(5,  "Done!  Really!",
+                 "Done!  Really!"),
+        Case(6,  "!missing.png!",
+                 "!missing.png!"),
+        Case(7,  "!https://example.com/a.png!",
+                 "![](https://example.com/a.png)"),
+        Case(8,  "!https://example.com/a.png|width=50!",
+                 `<img src="https://example.com/a.png" alt="a.png" width="50">`),
+        Case(9,  "See <^notes.pdf> and <^huge.png>.",
+                 "See [notes.pdf](files/LIBRA-1-notes.pdf) and huge.png."),
+        Case(10, "[the notes](^notes.pdf) and [big](^huge.png)",
+                 "[the notes](files/LIBRA-1-notes.pdf) and big"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := Attachments(tt.text, issue, locate); got != tt.want {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.text, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestExternalImages(t *testing.T) {
+    const fn = "ExternalImages"
+
+	type testCase struct {
+		name string
+		text string
+		want []string
+	}
+
+    Case := func(idx int, text string, want ...string) testCase {
+        return testCase{test.CaseName(fn, idx), text, want}
+    }
+
+	tests := []testCase{
+        Case(0, "no images"),
+        Case(1, "![x](https://a.com/1.png) ![](../blob/main/x.png) ![y](https://a.com/1.png)",
+                "https://a.com/1.png"),
+        Case(2, `<img src="https://a.com/2.png?a=1&amp;b=2" alt="2"> ![](http://b.com/3.gif)`,
+                "https://a.com/2.png?a=1&b=2", "http://b.com/3.gif"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := ExternalImages(tt.text)
+            if want := append([]string{}, tt.want...); !slices.Equal(got, want) {
+                t.Errorf("%s() = %q, want %q", fn, got, want)
+            }
+		})
+	}
+}
+
+func TestIsExternalUrl(t *testing.T) {
+    const fn = "IsExternalUrl"
+
+	type testCase struct {
+		name string
+		text string
+		want bool
+	}
+
+    Case := func(idx int, text string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), text, want}
+    }
+
+	tests := []testCase{
+        Case(0, "https://example.com/a.png", true),
+        Case(1, "http://example.com",        true),
+        Case(2, "ftp://example.com/a.png",   false),
+        Case(3, "https://",                  false),
+        Case(4, "image.png",                 false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := IsExternalUrl(tt.text); got != tt.want {
+                t.Errorf("%s(%q) = %v, want %v", fn, tt.text, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestByteSize(t *testing.T) {
+    const fn = "ByteSize"
+
+	type testCase struct {
+		name string
+		size int
+		want string
+	}
+
+    Case := func(idx int, size int, want string) testCase {
+        return testCase{test.CaseName(fn, idx), size, want}
+    }
+
+	tests := []testCase{
+        Case(0, 0,                     "0 B"),
+        Case(1, 1023,                  "1023 B"),
+        Case(2, 1536,                  "1.5 KB"),
+        Case(3, 12_900_000,            "12.3 MB"),
+        Case(4, 5 * 1024 * 1024 * 1024, "5.0 GB"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := ByteSize(tt.size); got != tt.want {
+                t.Errorf("%s(%d) = %q, want %q", fn, tt.size, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestImageTag(t *testing.T) {
+    const fn = "imageTag"
+
+	type testCase struct {
+		name    string
+		options string
+		want    string
+	}
+
+    Case := func(idx int, options, want string) testCase {
+        return testCase{test.CaseName(fn, idx), options, want}
+    }
+
+	tests := []testCase{
+        Case(0, "",
+                `<img src="x.png?a=1&amp;b=2" alt="x.png">`),
+        Case(1, "thumbnail",
+                `<a href="x.png?a=1&amp;b=2"><img src="x.png?a=1&amp;b=2" alt="x.png" width="200"></a>`),
+        Case(2, "thumbnail, height=80",
+                `<a href="x.png?a=1&amp;b=2"><img src="x.png?a=1&amp;b=2" alt="x.png" height="80"></a>`),
+        Case(3, "ALIGN=center,alt=A \"quoted\" name,title=T",
+                `<img src="x.png?a=1&amp;b=2" alt="A &#34;quoted&#34; name" title="T" align="center">`),
+        Case(4, "width=, border=1, onclick=evil()",
+                `<img src="x.png?a=1&amp;b=2" alt="x.png" border="1">`),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := imageTag("x.png", "x.png?a=1&b=2", tt.options); got != tt.want {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.options, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestReferencesAttachment(t *testing.T) {
+    const fn = "referencesAttachment"
+
+	type testCase struct {
+		name string
+		text string
+		want bool
+	}
+
+    Case := func(idx int, text string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), text, want}
+    }
+
+	tests := []testCase{
+        Case(0, "!a.png!",              true),
+        Case(1, "!a.png|thumbnail!",    true),
+        Case(2, "[^a.png]",             true),
+        Case(3, "[see this|^a.png]",    true),
+        Case(4, "a.png",                false),
+        Case(5, "!ba.png!",             false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := referencesAttachment(tt.text, "a.png"); got != tt.want {
+                t.Errorf("%s(%q) = %v, want %v", fn, tt.text, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Internal functions - test support
+// ============================================================================
+
+// A Jira issue with attachments of the given file names.
+func testIssue(key Jira.IssueKey, files ...string) Jira.Issue {
+    fields := &jira.IssueFields{}
+    for idx, file := range files {
+        fields.Attachments = append(fields.Attachments, &jira.Attachment{
+            ID:       fmt.Sprint(10000 + idx),
+            Filename: file,
+            Size:     1024 * (idx + 1),
+        })
+    }
+    return *Jira.NewIssueType(&Jira.Client{}, &jira.Issue{Key: key, Fields: fields})
+}
@@ -0,0 +1,92 @@
+// convert/board.go
+//
+// Conversion of Jira boards and sprints to GitHub projects.
+
+package convert
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The project field whose options are made from the columns of a Jira board.
+//  NOTE: this is the built-in field of a new GitHub project.
+const BOARD_STATUS_FIELD = "Status"
+
+// The project iteration field which is made from the sprints of a Jira board.
+const BOARD_SPRINT_FIELD = "Sprint"
+
+// The length in days given to a sprint which has not been scheduled in Jira.
+const SPRINT_DAYS = 14
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// The title of the GitHub project for a Jira board.
+func BoardTitle(board Jira.Board) string {
+    return "Jira board: " + board.Name
+}
+
+// The options of the status field for the columns of a Jira board.  The first
+// column is gray, the last is green, and those in between are yellow.
+func BoardColumns(board Jira.Board) []Github.FieldOption {
+    res  := make([]Github.FieldOption, 0, len(board.Columns))
+    last := len(board.Columns) - 1
+    for idx, column := range board.Columns {
+        color := "YELLOW"
+        switch idx {
+            case 0:    color = "GRAY"
+            case last: color = "GREEN"
+        }
+        res = append(res, Github.FieldOption{
+            Name:        column.Name,
+            Color:       color,
+            Description: "Jira board column",
+        })
+    }
+    return res
+}
+
+// The iterations for the sprints of a Jira board in chronological order.  A
+// sprint which has not been scheduled follows the sprint before it and lasts
+// SPRINT_DAYS.
+func BoardSprints(board Jira.Board) []Github.Iteration {
+    res  := make([]Github.Iteration, 0, len(board.Sprints))
+    next := time.Now().Truncate(24 * time.Hour)
+    for _, sprint := range board.Sprints {
+        iter := Github.Iteration{Title: sprint.Name, StartDate: next, Duration: SPRINT_DAYS}
+        if sprint.StartDate != nil {
+            iter.StartDate = *sprint.StartDate
+            if sprint.EndDate != nil {
+                days := math.Ceil(sprint.EndDate.Sub(*sprint.StartDate).Hours() / 24)
+                iter.Duration = max(int(days), 1)
+            }
+        }
+        next = iter.StartDate.AddDate(0, 0, iter.Duration)
+        res  = append(res, iter)
+    }
+    slices.SortStableFunc(res, func(a, b Github.Iteration) int {
+        return a.StartDate.Compare(b.StartDate)
+    })
+    return res
+}
+
+// The name of the sprint with the given ID on the Jira board.
+//  NOTE: returns an empty string if there is no such sprint.
+func SprintName(board Jira.Board, id int) string {
+    for _, sprint := range board.Sprints {
+        if sprint.ID == id {
+            return sprint.Name
+        }
+    }
+    return ""
+}
@@ -0,0 +1,114 @@
+// convert/board_test.go
+
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestBoardColumns(t *testing.T) {
+    const fn = "BoardColumns"
+
+    board := Jira.Board{Columns: []Jira.BoardColumn{
+        {Name: "To Do"}, {Name: "In Progress"}, {Name: "Review"}, {Name: "Done"},
+    }}
+    want := []string{"GRAY", "YELLOW", "YELLOW", "GREEN"}
+    got  := BoardColumns(board)
+    if len(got) != len(want) {
+        t.Fatalf("%s() = %d options, want %d", fn, len(got), len(want))
+    }
+    for idx, option := range got {
+        if option.Name != board.Columns[idx].Name {
+            t.Errorf("%s()[%d].Name = %q, want %q", fn, idx, option.Name, board.Columns[idx].Name)
+        }
+        if option.Color != want[idx] {
+            t.Errorf("%s()[%d].Color = %q, want %q", fn, idx, option.Color, want[idx])
+        }
+    }
+    if got := BoardColumns(Jira.Board{}); len(got) != 0 {
+        t.Errorf("%s() without columns = %d options, want 0", fn, len(got))
+    }
+}
+
+func TestBoardSprints(t *testing.T) {
+    const fn = "BoardSprints"
+
+	type iteration struct {
+		title    string
+		start    time.Time
+		duration int
+	}
+	type testCase struct {
+		name    string
+		sprints []Jira.Sprint
+		want    []iteration
+	}
+
+    day   := func(d int) *time.Time { at := time.Date(2024, 3, d, 9, 0, 0, 0, time.UTC); return &at }
+    today := time.Now().Truncate(24 * time.Hour)
+    Case  := func(idx int, sprints []Jira.Sprint, want ...iteration) testCase {
+        return testCase{test.CaseName(fn, idx), sprints, want}
+    }
+
+	tests := []testCase{
+        Case(0, nil),
+        Case(1, []Jira.Sprint{
+                {Name: "S1", StartDate: day(1), EndDate: day(15)},
+                {Name: "S2", StartDate: day(15), EndDate: day(22)},
+            },
+            iteration{"S1", *day(1), 14},
+            iteration{"S2", *day(15), 7}),
+        Case(2, []Jira.Sprint{
+                {Name: "S1", StartDate: day(1), EndDate: day(8)},
+                {Name: "S2"},
+                {Name: "S3", StartDate: day(1)},
+            },
+            iteration{"S1", *day(1), 7},
+            iteration{"S3", *day(1), SPRINT_DAYS},
+            iteration{"S2", *day(8), SPRINT_DAYS}),
+        Case(3, []Jira.Sprint{
+                {Name: "S1", StartDate: day(1), EndDate: day(1)},
+                {Name: "Future"},
+            },
+            iteration{"S1", *day(1), 1},
+            iteration{"Future", *day(2), SPRINT_DAYS}),
+        Case(4, []Jira.Sprint{{Name: "Unscheduled"}},
+            iteration{"Unscheduled", today, SPRINT_DAYS}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := BoardSprints(Jira.Board{Sprints: tt.sprints})
+            if len(got) != len(tt.want) {
+                t.Fatalf("%s() = %d iterations, want %d", fn, len(got), len(tt.want))
+            }
+            for idx, want := range tt.want {
+                g := got[idx]
+                if (g.Title != want.title) || !g.StartDate.Equal(want.start) || (g.Duration != want.duration) {
+                    t.Errorf("%s()[%d] = {%s %v %d}, want {%s %v %d}", fn, idx,
+                        g.Title, g.StartDate, g.Duration, want.title, want.start, want.duration)
+                }
+            }
+		})
+	}
+}
+
+func TestSprintName(t *testing.T) {
+    const fn = "SprintName"
+
+    board := Jira.Board{Sprints: []Jira.Sprint{{ID: 10, Name: "S1"}, {ID: 11, Name: "S2"}}}
+    if got := SprintName(board, 11); got != "S2" {
+        t.Errorf("%s(11) = %q, want %q", fn, got, "S2")
+    }
+    if got := SprintName(board, 12); got != "" {
+        t.Errorf("%s(12) = %q, want blank", fn, got)
+    }
+}
@@ -0,0 +1,228 @@
+// convert/field.go
+//
+// Mapping of Jira custom fields to GitHub.
+//
+// FIELD_MAP_FILE holds a JSON object which maps the name of each Jira custom
+// field to its disposition, e.g.:
+//
+//  {
+//      "Story Points": { "as": "project", "type": "number" },
+//      "Team":         { "as": "label" },
+//      "Due By":       { "as": "annotation" },
+//      "Rank":         { "as": "drop" },
+//      "*":            { "as": "drop" }
+//  }
+//
+// The "*" entry applies to custom fields which are not otherwise listed.
+
+package convert
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"lib.virginia.edu/agita/log"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// The disposition of a Jira custom field.
+type FieldMapping struct {
+    As      string  `json:"as"`             // FIELD_LABEL, FIELD_ANNOTATION, FIELD_PROJECT or FIELD_DROP.
+    Name    string  `json:"name,omitempty"` // GitHub name if not the Jira field name.
+    Type    string  `json:"type,omitempty"` // For FIELD_PROJECT: "text", "number" or "date".
+}
+
+// The value of a mapped Jira custom field.
+type FieldValue struct {
+    Name    string  // GitHub name (label prefix, annotation key or project field).
+    Type    string  // For FIELD_PROJECT: "text", "number" or "date".
+    Value   string  // The Jira value rendered as a string.
+}
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// Path relative to project root of the custom field mapping file.
+const FIELD_MAP_FILE = "fields.json"
+
+// Custom field dispositions.
+const (
+    FIELD_LABEL      = "label"
+    FIELD_ANNOTATION = "annotation"
+    FIELD_PROJECT    = "project"
+    FIELD_DROP       = "drop"
+)
+
+// The FieldMap entry which applies to custom fields not otherwise listed.
+const FIELD_DEFAULT = "*"
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// The disposition of each Jira custom field by name, loaded from
+// FIELD_MAP_FILE.
+var FieldMap = map[string]FieldMapping{}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// The disposition of the named Jira custom field.
+func FieldMappingFor(name string) FieldMapping {
+    mapping, found := FieldMap[name]
+    if !found {
+        mapping, found = FieldMap[FIELD_DEFAULT]
+    }
+    if !found || (mapping.As == "") {
+        mapping.As = FIELD_DROP
+    }
+    if mapping.Name == "" {
+        mapping.Name = name
+    }
+    return mapping
+}
+
+// The values of the custom fields with the given disposition, ordered by name.
+//  NOTE: empty values are not included.
+func FieldValues(values map[string]any, as string) []FieldValue {
+    res := []FieldValue{}
+    for name, value := range values {
+        mapping := FieldMappingFor(name)
+        if mapping.As != as {
+            continue
+        }
+        if str := Jira.FieldString(value); str != "" {
+            res = append(res, FieldValue{Name: mapping.Name, Type: mapping.Type, Value: str})
+        }
+    }
+    slices.SortFunc(res, func(a, b FieldValue) int { return cmp.Compare(a.Name, b.Name) })
+    return res
+}
+
+// The labels for the custom fields of the Jira issue which are mapped to
+// labels; each element of a multi-valued field becomes a separate label.
+func CustomFieldLabels(issue Jira.Issue) []Github.Label {
+    res := []Github.Label{}
+    for name, value := range issue.CustomFields() {
+        mapping := FieldMappingFor(name)
+        if mapping.As != FIELD_LABEL {
+            continue
+        }
+        scheme := LabelScheme{
+            Field:          name,
+            Prefix:         labelSlug(mapping.Name) + ":",
+            Color:          "ededed",
+            Description:    "Jira " + name + " %q",
+        }
+        items, multi := value.([]any)
+        if !multi {
+            items = []any{value}
+        }
+        for _, item := range items {
+            if label, ok := scheme.Label(Jira.FieldString(item)); ok {
+                res = append(res, label)
+            }
+        }
+    }
+    slices.SortFunc(res, func(a, b Github.Label) int { return cmp.Compare(a.Name, b.Name) })
+    return res
+}
+
+// The GitHub project fields for custom fields and their types by name.
+func ProjectFields() map[string]string {
+    res := map[string]string{}
+    for _, name := range ProjectFieldSources() {
+        mapping := FieldMappingFor(name)
+        res[mapping.Name] = mapping.Type
+    }
+    return res
+}
+
+// The names of the Jira custom fields which are mapped to GitHub project
+// fields.
+func ProjectFieldSources() []string {
+    res := []string{}
+    for name, mapping := range FieldMap {
+        if (name != FIELD_DEFAULT) && (mapping.As == FIELD_PROJECT) {
+            res = append(res, name)
+        }
+    }
+    return res
+}
+
+// The values of the custom fields of the Jira issue which are not dropped, by
+// Jira field name, for "-export".
+func CustomFieldsToJson(issue Jira.Issue) string {
+    values := map[string]any{}
+    for name, value := range issue.CustomFields() {
+        if FieldMappingFor(name).As != FIELD_DROP {
+            values[name] = value
+        }
+    }
+    if len(values) == 0 {
+        return ""
+    }
+    if bytes, err := json.Marshal(values); log.ErrorValue(err) == nil {
+        return string(bytes)
+    } else {
+        return ""
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Load FieldMap from FIELD_MAP_FILE.
+//  NOTE: FieldMap is left empty if the file does not exist.
+func loadFieldMap() {
+    path := filepath.Join(util.RootPath(), FIELD_MAP_FILE)
+    data, err := os.ReadFile(path)
+    if errors.Is(err, os.ErrNotExist) {
+        return
+    } else if log.ErrorValue(err) != nil {
+        return
+    }
+    mapping := map[string]FieldMapping{}
+    if log.ErrorValue(json.Unmarshal(data, &mapping)) == nil {
+        FieldMap = mapping
+    }
+}
+
+// ============================================================================
+// Module initialization
+// ============================================================================
+
+// Load the custom field mapping and have the Jira search return the custom
+// fields which are not dropped.
+func init() {
+    loadFieldMap()
+    names := []string{}
+    if FieldMappingFor(FIELD_DEFAULT).As != FIELD_DROP {
+        for id, name := range Jira.FieldNameByID {
+            if Jira.IsCustomField(id) && (FieldMappingFor(name).As != FIELD_DROP) {
+                names = append(names, name)
+            }
+        }
+    } else {
+        for name, mapping := range FieldMap {
+            if (name != FIELD_DEFAULT) && (mapping.As != FIELD_DROP) {
+                names = append(names, name)
+            }
+        }
+    }
+    Jira.AddSearchFields(names...)
+}
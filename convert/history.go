@@ -0,0 +1,117 @@
+// convert/history.go
+//
+// Conversion of the Jira changelog of an issue into GitHub issue comments.
+
+package convert
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The heading of a comment replaying the Jira changelog.
+const HISTORY_HEADING = "Jira history"
+
+// Values longer than this are shortened in the tables of history and worklog
+// comments.
+const TABLE_VALUE_MAX = 200
+
+// A history or worklog comment is split into several comments to keep each one
+// from growing beyond this size.
+const TABLE_COMMENT_MAX = 60000
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Render the Jira changelog of the issue as one or more comments containing a
+// table of changes in chronological order.  Each comment has the time of its
+// last change as its creation time.
+//  NOTE: returns an empty result if the issue has no changelog.
+func History(issue Jira.Issue) []*Github.CommentImport {
+    changes := issue.Changes()
+    slices.SortStableFunc(changes, func(a, b Jira.Change) int {
+        return Github.MakeTime(a.Created).Compare(Github.MakeTime(b.Created).Time)
+    })
+    rows  := make([]string, 0, len(changes))
+    times := make([]string, 0, len(changes))
+    for _, change := range changes {
+        rows  = append(rows, historyRow(change))
+        times = append(times, change.Created)
+    }
+    return tableComments(HISTORY_HEADING, "||Date||Author||Field||From||To||", rows, times)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Render the rows of a Jira table as one or more comments, splitting the table
+// into chunks so that no comment grows beyond TABLE_COMMENT_MAX.  Each
+// comment has the time associated with its last row as its creation time.
+//  NOTE: returns an empty result if there are no rows.
+func tableComments(heading, header string, rows, times []string) []*Github.CommentImport {
+    chunks := [][]string{}
+    last   := []string{}
+    size   := 0
+    for idx, row := range rows {
+        if (len(chunks) == 0) || (size + len(row) > TABLE_COMMENT_MAX) {
+            chunks = append(chunks, []string{})
+            last   = append(last, "")
+            size   = 0
+        }
+        end := len(chunks) - 1
+        chunks[end] = append(chunks[end], row)
+        last[end]   = times[idx]
+        size += len(row) + 1
+    }
+    res := make([]*Github.CommentImport, 0, len(chunks))
+    for idx, chunk := range chunks {
+        title := heading
+        if len(chunks) > 1 {
+            title = fmt.Sprintf("%s (%d of %d)", title, idx + 1, len(chunks))
+        }
+        lines := []string{"*" + title + "*", "", header}
+        lines  = append(lines, chunk...)
+        fld   := map[string]any{
+            "CreatedAt": Github.MakeTime(last[idx]),
+            "Body":      strings.Join(lines, "\n"),
+        }
+        res = append(res, Github.NewCommentImport(fld))
+    }
+    return res
+}
+
+// Render a change as a row of a Jira table.
+func historyRow(change Jira.Change) string {
+    date   := Github.MakeTime(change.Created).Format("2006-01-02 15:04")
+    author := Jira.AppendFullName(change.Author)
+    cells  := []string{date, author, change.Field, change.From, change.To}
+    for idx, cell := range cells {
+        cells[idx] = tableCell(cell)
+    }
+    return "|" + strings.Join(cells, "|") + "|"
+}
+
+// Make a value suitable for a table cell: on a single line, without cell
+// separators, and not so long that it overwhelms the table.
+//  NOTE: an empty cell would be taken as the start of a header cell.
+func tableCell(value string) string {
+    value = strings.Join(strings.Fields(value), " ")
+    value = strings.ReplaceAll(value, "|", "&#124;")
+    if runes := []rune(value); len(runes) > TABLE_VALUE_MAX {
+        value = string(runes[:TABLE_VALUE_MAX]) + "..."
+    }
+    if value == "" {
+        value = " "
+    }
+    return value
+}
@@ -6,6 +6,7 @@ package convert
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"lib.virginia.edu/agita/log"
@@ -56,20 +57,35 @@ func Issue(issue Jira.Issue) *Github.IssueImport {
     // If the assignee does not have an equivalent GitHub account, then it is
     // added to the annotations.
     assignee := issue.Assignee()
-    if githubUser := JiraToGithubUser[assignee]; githubUser != "" {
+    if githubUser := JiraToGithubUser(assignee); githubUser != "" {
         assignee = githubUser
     } else {
         note["Assignee"] = Jira.AppendFullName(assignee)
         assignee = ""
     }
 
+    // Jira labels are supplemented by labels synthesized from other fields.
+    labels := slices.Clone(issue.Labels())
+    labels  = append(labels, LabelNames(SchemeLabels(issue))...)
+
+    // The issue is closed according to its Jira status category; if there is
+    // no resolution date then the time of the last update is used instead.
+    closed   := Closed(issue)
+    closedAt := Jira.Time{}
+    if closed {
+        if closedAt = issue.Resolutiondate(); !Jira.ValidTime(closedAt) {
+            closedAt = issue.Updated()
+        }
+    }
+
     add("Title",        title)
     add("Body",         desc)
     add("CreatedAt",    issue.Created())
-    add("ClosedAt",     issue.Resolutiondate())
+    add("ClosedAt",     closedAt)
+    add("Closed",       closed)
     add("UpdatedAt",    issue.Updated())
     add("Assignee",     assignee)
-    add("Labels",       issue.Labels())
+    add("Labels",       labels)
 
     if lines := issueAnnotations(issue, note, skip); len(lines) > 0 {
         notes := strings.Join(lines, "\n")
@@ -85,15 +101,19 @@ func Issue(issue Jira.Issue) *Github.IssueImport {
 
 // Generate lines to annotate the issue body with Jira issue properties that
 // have no (updateable) GitHub issue equivalent.
+//  NOTE: names are padded to the longest name of the lines generated.
 func issueAnnotations(issue Jira.Issue, added map[string]any, skipped map[string]bool) []string {
-    res  := []string{}
-    tag  := Github.ISSUE_ANNOTATION_TAG
-    max  := util.CharCount("Resolution")
-    note := func(key string, jiraValue any) {
+    keys   := []string{}
+    values := []any{}
+    max    := 0
+    note   := func(key string, jiraValue any) {
         if !skipped[key] {
             if githubValue, use := From(jiraValue); use {
-                line := fmt.Sprintf("%s %-*s = %v", tag, max, key, githubValue)
-                res = append(res, line)
+                keys   = append(keys, key)
+                values = append(values, githubValue)
+                if count := util.CharCount(key); count > max {
+                    max = count
+                }
             }
         }
     }
@@ -118,6 +138,23 @@ func issueAnnotations(issue Jira.Issue, added map[string]any, skipped map[string
     note("Priority",    issue.Priority())
     note("Status",      issue.Status())
     note("Resolution",  issue.Resolution())
+    note("Parent",      issue.Parent())
+    note("Subtasks",    strings.Join(issue.Subtasks(), ", "))
+    note("FixVersions", strings.Join(issue.FixVersions(), ", "))
+    note("Epic",        issue.Epic())
+    note("Estimate",    TimeTracking(issue.OriginalEstimate(), issue.AggregateOriginalEstimate()))
+    note("Remaining",   TimeTracking(issue.RemainingEstimate(), issue.AggregateRemainingEstimate()))
+    note("TimeSpent",   TimeTracking(issue.TimeSpent(), issue.AggregateTimeSpent()))
+
+    // Custom fields mapped to annotations.
+    for _, field := range FieldValues(issue.CustomFields(), FIELD_ANNOTATION) {
+        note(field.Name, field.Value)
+    }
 
+    res := make([]string, 0, len(keys))
+    tag := Github.ISSUE_ANNOTATION_TAG
+    for idx, key := range keys {
+        res = append(res, fmt.Sprintf("%s %-*s = %v", tag, max, key, values[idx]))
+    }
     return res
 }
@@ -0,0 +1,64 @@
+// convert/issue_test.go
+
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestIssueAnnotations(t *testing.T) {
+    const fn = "issueAnnotations"
+
+	type testCase struct {
+		name  string
+		added map[string]any
+		want  []string
+	}
+
+    tag  := Github.ISSUE_ANNOTATION_TAG
+    Case := func(idx int, added map[string]any, want ...string) testCase {
+        return testCase{test.CaseName(fn, idx), added, want}
+    }
+
+	tests := []testCase{
+        Case(0, nil,
+                tag + " Type   = Bug",
+                tag + " Status = Open"),
+        Case(1, map[string]any{"FixVersions": "1.2"},
+                tag + " FixVersions = 1.2",
+                tag + " Type        = Bug",
+                tag + " Status      = Open"),
+        Case(2, map[string]any{"Customer Deadline": "2024-05-01"},
+                tag + " Customer Deadline = 2024-05-01",
+                tag + " Type              = Bug",
+                tag + " Status            = Open"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            issue := *Jira.NewIssueType(&Jira.Client{}, &jira.Issue{
+                Key: "TEST-1",
+                Fields: &jira.IssueFields{
+                    Type:   jira.IssueType{Name: "Bug"},
+                    Status: &jira.Status{Name: "Open"},
+                },
+            })
+            got  := strings.Join(issueAnnotations(issue, tt.added, nil), "\n")
+            want := strings.Join(tt.want, "\n")
+            if got != want {
+                t.Errorf("%s() =\n%s\nwant\n%s", fn, got, want)
+            }
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// convert/issue_type.go
+//
+// Mapping of Jira issue types to GitHub issue types.
+
+package convert
+
+import (
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// The GitHub issue types which are created for the organization if missing.
+// (These are the default types of a new organization.)
+var GITHUB_ISSUE_TYPES = []Github.IssueType{
+    {Name: "Task",    Color: "YELLOW", Description: "A specific piece of work"},
+    {Name: "Bug",     Color: "RED",    Description: "An unexpected problem or behavior"},
+    {Name: "Feature", Color: "BLUE",   Description: "A request, idea, or new functionality"},
+}
+
+// A mapping of Jira issue type to its matching GitHub issue type.
+// A Jira issue type which does not appear here is represented by a label
+// generated according to TYPE_LABEL_SCHEME.
+var JiraToGithubType = map[string]string {
+    "Bug":              "Bug",
+    "Story":            "Feature",
+    "New Feature":      "Feature",
+    "Improvement":      "Feature",
+    "Epic":             "Feature",
+    "Task":             "Task",
+    "Sub-task":         "Task",
+    "Service Request":  "Task",
+}
+
+// The label scheme for Jira issue types with no GitHub issue type.
+var TYPE_LABEL_SCHEME = LabelScheme{
+    Field:          "Type",
+    Prefix:         "type:",
+    Color:          "d4c5f9",
+    Description:    "Jira issue type %q",
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// The name of the GitHub issue type for the Jira issue.
+//  NOTE: returns blank if the Jira issue type is not mapped.
+func IssueType(issue Jira.Issue) string {
+    return JiraToGithubType[issue.Type()]
+}
+
+// The label representing the type of a Jira issue with no GitHub issue type.
+//  NOTE: returns false if the Jira issue type is mapped.
+func IssueTypeLabel(issue Jira.Issue) (Github.Label, bool) {
+    if (issue.Type() == "") || (IssueType(issue) != "") {
+        return Github.Label{}, false
+    }
+    return TYPE_LABEL_SCHEME.Label(issue.Type())
+}
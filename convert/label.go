@@ -0,0 +1,157 @@
+// convert/label.go
+//
+// Synthesis of GitHub labels from Jira issue fields.
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// How GitHub labels are synthesized from the values of a Jira issue field.
+type LabelScheme struct {
+    Field       string              // "Component", "Priority", "Status", or "Type"
+    Prefix      string              // Label name prefix (e.g. "priority:").
+    Color       string              // Default label color.
+    Colors      map[string]string   // Label color by Jira value.
+    Description string              // Label description format given the Jira value.
+}
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// The schemes used to generate labels for each transferred issue in addition
+// to the issue's Jira labels.  A Jira value like "In Review" becomes a label
+// like "status:in-review".
+var LABEL_SCHEMES = []LabelScheme{
+    {
+        Field:          "Component",
+        Prefix:         "component:",
+        Color:          "c5def5",
+        Description:    "Jira component %q",
+    },
+    {
+        Field:          "Priority",
+        Prefix:         "priority:",
+        Color:          "fbca04",
+        Colors:         map[string]string{
+            "Blocker":  "b60205",
+            "Critical": "d93f0b",
+            "Major":    "fbca04",
+            "Minor":    "0e8a16",
+            "Trivial":  "c2e0c6",
+        },
+        Description:    "Jira priority %q",
+    },
+    {
+        Field:          "Status",
+        Prefix:         "status:",
+        Color:          "bfdadc",
+        Description:    "Jira status %q",
+    },
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// The labels synthesized from the Jira issue according to LABEL_SCHEMES, along
+// with a label for an issue type which has no GitHub equivalent and labels for
+// custom fields mapped to labels.
+func SchemeLabels(issue Jira.Issue) []Github.Label {
+    res := []Github.Label{}
+    for _, scheme := range LABEL_SCHEMES {
+        for _, value := range schemeValues(issue, scheme.Field) {
+            if label, ok := scheme.Label(value); ok {
+                res = append(res, label)
+            }
+        }
+    }
+    if label, ok := IssueTypeLabel(issue); ok {
+        res = append(res, label)
+    }
+    res = append(res, CustomFieldLabels(issue)...)
+    return res
+}
+
+// The names of the given labels.
+func LabelNames(labels []Github.Label) []string {
+    res := make([]string, 0, len(labels))
+    for _, label := range labels {
+        res = append(res, label.Name)
+    }
+    return res
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// Generate the label for a Jira value.
+//  NOTE: returns false if the value has no usable characters.
+func (s LabelScheme) Label(value string) (Github.Label, bool) {
+    slug := labelSlug(value)
+    if slug == "" {
+        return Github.Label{}, false
+    }
+    name  := truncate(s.Prefix + slug, Github.LABEL_NAME_MAX)
+    color := s.Colors[value]
+    if color == "" {
+        color = s.Color
+    }
+    desc := truncate(fmt.Sprintf(s.Description, value), Github.LABEL_DESCRIPTION_MAX)
+    return Github.Label{Name: name, Color: color, Description: desc}, true
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The values of the named Jira issue field.
+func schemeValues(issue Jira.Issue, field string) []string {
+    var res []string
+    switch field {
+        case "Component": res = issue.Components()
+        case "Priority":  res = []string{issue.Priority()}
+        case "Status":    res = []string{issue.Status()}
+        case "Type":      res = []string{issue.Type()}
+    }
+    return res
+}
+
+// Lowercase the value and replace each run of characters other than letters
+// and digits with a single hyphen.
+func labelSlug(value string) string {
+    var b strings.Builder
+    hyphen := false
+    for _, r := range strings.ToLower(value) {
+        if unicode.IsLetter(r) || unicode.IsDigit(r) {
+            if hyphen && (b.Len() > 0) {
+                b.WriteRune('-')
+            }
+            b.WriteRune(r)
+            hyphen = false
+        } else {
+            hyphen = true
+        }
+    }
+    return b.String()
+}
+
+// Limit the string to the given number of characters.
+func truncate(s string, max int) string {
+    if runes := []rune(s); len(runes) > max {
+        return string(runes[:max])
+    }
+    return s
+}
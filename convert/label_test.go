@@ -0,0 +1,136 @@
+// convert/label_test.go
+
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Github"
+)
+
+// ============================================================================
+// Tests - Exported methods
+// ============================================================================
+
+func TestLabelSchemeLabel(t *testing.T) {
+    const fn = "LabelScheme.Label"
+
+	type testCase struct {
+		name  string
+		value string
+		want  Github.Label
+		ok    bool
+	}
+
+    scheme := LabelScheme{
+        Prefix:      "priority:",
+        Color:       "fbca04",
+        Colors:      map[string]string{"Blocker": "b60205"},
+        Description: "Jira priority %q",
+    }
+    Case := func(idx int, value, name, color string, ok bool) testCase {
+        tc := testCase{name: test.CaseName(fn, idx), value: value, ok: ok}
+        if ok {
+            tc.want = Github.Label{Name: name, Color: color, Description: `Jira priority "` + value + `"`}
+        }
+        return tc
+    }
+
+	tests := []testCase{
+        Case(0, "Blocker",  "priority:blocker",  "b60205", true),
+        Case(1, "Major",    "priority:major",    "fbca04", true),
+        Case(2, "In Review", "priority:in-review", "fbca04", true),
+        Case(3, " -- ",     "",                  "",       false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got, ok := scheme.Label(tt.value)
+            if (ok != tt.ok) || (got != tt.want) {
+                t.Errorf("%s(%q) = (%+v, %v), want (%+v, %v)", fn, tt.value, got, ok, tt.want, tt.ok)
+            }
+		})
+	}
+}
+
+func TestLabelSchemeLimits(t *testing.T) {
+    const fn = "LabelScheme.Label"
+
+    scheme := LabelScheme{Prefix: "component:", Description: "Jira component %q"}
+    value  := strings.Repeat("x", 2 * Github.LABEL_DESCRIPTION_MAX)
+    got, ok := scheme.Label(value)
+    if !ok {
+        t.Fatalf("%s() = false, want true", fn)
+    }
+    if n := len([]rune(got.Name)); n != Github.LABEL_NAME_MAX {
+        t.Errorf("%s() name length = %d, want %d", fn, n, Github.LABEL_NAME_MAX)
+    }
+    if n := len([]rune(got.Description)); n != Github.LABEL_DESCRIPTION_MAX {
+        t.Errorf("%s() description length = %d, want %d", fn, n, Github.LABEL_DESCRIPTION_MAX)
+    }
+}
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestLabelSlug(t *testing.T) {
+    const fn = "labelSlug"
+
+	type testCase struct {
+		name  string
+		value string
+		want  string
+	}
+
+    Case := func(idx int, value, want string) testCase {
+        return testCase{test.CaseName(fn, idx), value, want}
+    }
+
+	tests := []testCase{
+        Case(0, "In Review",            "in-review"),
+        Case(1, "  Won't Fix!  ",       "won-t-fix"),
+        Case(2, "UI/UX -- Design",      "ui-ux-design"),
+        Case(3, "Café 2",               "café-2"),
+        Case(4, "***",                  ""),
+        Case(5, "",                     ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := labelSlug(tt.value); got != tt.want {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.value, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+    const fn = "truncate"
+
+	type testCase struct {
+		name  string
+		value string
+		max   int
+		want  string
+	}
+
+    Case := func(idx int, value string, max int, want string) testCase {
+        return testCase{test.CaseName(fn, idx), value, max, want}
+    }
+
+	tests := []testCase{
+        Case(0, "abcdef", 3, "abc"),
+        Case(1, "abc",    3, "abc"),
+        Case(2, "ab",     3, "ab"),
+        Case(3, "éèêë",   2, "éè"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := truncate(tt.value, tt.max); got != tt.want {
+                t.Errorf("%s(%q, %d) = %q, want %q", fn, tt.value, tt.max, got, tt.want)
+            }
+		})
+	}
+}
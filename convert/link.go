@@ -0,0 +1,84 @@
+// convert/link.go
+//
+// Conversion of Jira issue links.
+
+package convert
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The heading of the section listing linked issues in a GitHub issue body.
+const LINKED_ISSUES_HEADING = "Linked issues"
+
+// Jira link type for "blocks" and "is blocked by".
+const JIRA_LINK_BLOCKS = "Blocks"
+
+// Jira link type for "duplicates" and "is duplicated by".
+const JIRA_LINK_DUPLICATE = "Duplicate"
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Generate a Markdown section listing the Jira issues linked to the issue,
+// grouped by relationship (e.g. "is blocked by") in order of appearance.
+//  NOTE: linked issues are given by their keys for conversion by References().
+//  NOTE: returns blank if the issue has no links.
+func LinkedIssues(issue Jira.Issue) string {
+    relations := []string{}
+    keys      := map[string][]string{}
+    for _, link := range issue.Links() {
+        rel := link.Relation
+        if rel == "" {
+            rel = strings.ToLower(link.Type)
+        }
+        if !slices.Contains(relations, rel) {
+            relations = append(relations, rel)
+        }
+        if !slices.Contains(keys[rel], link.Key) {
+            keys[rel] = append(keys[rel], link.Key)
+        }
+    }
+    if len(relations) == 0 {
+        return ""
+    }
+    lines := []string{"**" + LINKED_ISSUES_HEADING + "**", ""}
+    for _, rel := range relations {
+        lines = append(lines, fmt.Sprintf("* %s: %s", rel, strings.Join(keys[rel], ", ")))
+    }
+    return strings.Join(lines, "\n")
+}
+
+// The keys of the Jira issues which block the issue.
+func BlockedBy(issue Jira.Issue) []string {
+    res := []string{}
+    for _, link := range issue.Links() {
+        if (link.Type == JIRA_LINK_BLOCKS) && !link.Outward {
+            res = append(res, link.Key)
+        }
+    }
+    return res
+}
+
+// The key of the Jira issue of which a resolved issue is a duplicate.
+//  NOTE: returns blank if the issue is unresolved or not a duplicate.
+func DuplicateOf(issue Jira.Issue) string {
+    if issue.Resolution() == "" {
+        return ""
+    }
+    for _, link := range issue.Links() {
+        if (link.Type == JIRA_LINK_DUPLICATE) && link.Outward {
+            return link.Key
+        }
+    }
+    return ""
+}
@@ -0,0 +1,190 @@
+// convert/link_test.go
+
+package convert
+
+import (
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Jira"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestLinkedIssues(t *testing.T) {
+    const fn = "LinkedIssues"
+
+	type testCase struct {
+		name       string
+		links      []*jira.IssueLink
+		want       string
+		unresolved []string
+	}
+
+    const (
+        issueURL = "https://github.com/uvalib/Libra2/issues/7"
+        heading  = "**" + LINKED_ISSUES_HEADING + "**\n\n"
+    )
+    resolve := func(key Jira.IssueKey, _ string) string {
+        if key == "LIBRA-1" {
+            return issueURL
+        }
+        return ""
+    }
+    Case := func(idx int, links []*jira.IssueLink, want string, unresolved ...string) testCase {
+        return testCase{test.CaseName(fn, idx), links, want, unresolved}
+    }
+
+	tests := []testCase{
+        Case(0, nil,
+                ""),
+        Case(1, testLinks(testLink(JIRA_LINK_BLOCKS, true, "LIBRA-1")),
+                heading + "* blocks: [LIBRA-1](" + issueURL + ")"),
+        Case(2, testLinks(testLink(JIRA_LINK_BLOCKS, false, "LIBRA-1")),
+                heading + "* is blocked by: [LIBRA-1](" + issueURL + ")"),
+        Case(3, testLinks(
+                    testLink(JIRA_LINK_BLOCKS, false, "LIBRA-1"),
+                    testLink(JIRA_LINK_DUPLICATE, true, "LIBRA-2"),
+                    testLink(JIRA_LINK_BLOCKS, false, "EMMA-3"),
+                    testLink(JIRA_LINK_BLOCKS, false, "EMMA-3"),
+                ),
+                heading + "* is blocked by: [LIBRA-1](" + issueURL + "), EMMA-3\n" +
+                          "* duplicates: LIBRA-2",
+                "EMMA-3", "LIBRA-2"),
+        Case(4, testLinks(&jira.IssueLink{
+                    Type:         jira.IssueLinkType{Name: "Clones"},
+                    OutwardIssue: &jira.Issue{Key: "LIBRA-1"},
+                }),
+                heading + "* clones: [LIBRA-1](" + issueURL + ")"),
+        Case(5, testLinks(nil, &jira.IssueLink{Type: jira.IssueLinkType{Name: "Relates"}}),
+                ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            issue := testLinkedIssue("", tt.links)
+            got, unresolved := References(LinkedIssues(issue), resolve)
+            if got != tt.want {
+                t.Errorf("%s() = %q, want %q", fn, got, tt.want)
+            }
+            if want := append([]string{}, tt.unresolved...); !slices.Equal(unresolved, want) {
+                t.Errorf("%s() unresolved = %q, want %q", fn, unresolved, want)
+            }
+		})
+	}
+}
+
+func TestBlockedBy(t *testing.T) {
+    const fn = "BlockedBy"
+
+	type testCase struct {
+		name  string
+		links []*jira.IssueLink
+		want  []string
+	}
+
+    Case := func(idx int, links []*jira.IssueLink, want ...string) testCase {
+        return testCase{test.CaseName(fn, idx), links, want}
+    }
+
+	tests := []testCase{
+        Case(0, nil),
+        Case(1, testLinks(testLink(JIRA_LINK_BLOCKS, false, "LIBRA-1")),
+                "LIBRA-1"),
+        Case(2, testLinks(testLink(JIRA_LINK_BLOCKS, true, "LIBRA-1"))),
+        Case(3, testLinks(
+                    testLink(JIRA_LINK_BLOCKS,    false, "LIBRA-1"),
+                    testLink(JIRA_LINK_DUPLICATE, false, "LIBRA-2"),
+                    testLink("Relates",           false, "LIBRA-3"),
+                    testLink(JIRA_LINK_BLOCKS,    false, "EMMA-4"),
+                ),
+                "LIBRA-1", "EMMA-4"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got  := BlockedBy(testLinkedIssue("", tt.links))
+            want := append([]string{}, tt.want...)
+            if !slices.Equal(got, want) {
+                t.Errorf("%s() = %q, want %q", fn, got, want)
+            }
+		})
+	}
+}
+
+func TestDuplicateOf(t *testing.T) {
+    const fn = "DuplicateOf"
+
+	type testCase struct {
+		name       string
+		resolution string
+		links      []*jira.IssueLink
+		want       string
+	}
+
+    Case := func(idx int, resolution string, links []*jira.IssueLink, want string) testCase {
+        return testCase{test.CaseName(fn, idx), resolution, links, want}
+    }
+
+	tests := []testCase{
+        Case(0, "Duplicate", testLinks(testLink(JIRA_LINK_DUPLICATE, true,  "LIBRA-1")), "LIBRA-1"),
+        Case(1, "Duplicate", testLinks(testLink(JIRA_LINK_DUPLICATE, false, "LIBRA-1")), ""),
+        Case(2, "",          testLinks(testLink(JIRA_LINK_DUPLICATE, true,  "LIBRA-1")), ""),
+        Case(3, "Duplicate", testLinks(testLink("Relates",           true,  "LIBRA-1")), ""),
+        Case(4, "Duplicate", nil,                                                        ""),
+        Case(5, "Duplicate", testLinks(
+                                 testLink(JIRA_LINK_BLOCKS,    true, "LIBRA-1"),
+                                 testLink(JIRA_LINK_DUPLICATE, true, "EMMA-2"),
+                             ), "EMMA-2"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            issue := testLinkedIssue(tt.resolution, tt.links)
+            if got := DuplicateOf(issue); got != tt.want {
+                t.Errorf("%s() = %q, want %q", fn, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Internal functions - test support
+// ============================================================================
+
+// A Jira issue with the given resolution (if not blank) and links.
+func testLinkedIssue(resolution string, links []*jira.IssueLink) Jira.Issue {
+    fields := &jira.IssueFields{IssueLinks: links}
+    if resolution != "" {
+        fields.Resolution = &jira.Resolution{Name: resolution}
+    }
+    return *Jira.NewIssueType(&Jira.Client{}, &jira.Issue{Key: "TEST-1", Fields: fields})
+}
+
+// A list of Jira issue links.
+func testLinks(links ...*jira.IssueLink) []*jira.IssueLink {
+    return links
+}
+
+// A Jira issue link of the given type to another issue, where `outward`
+// indicates that the issue is the source of the link.
+func testLink(linkType string, outward bool, key Jira.IssueKey) *jira.IssueLink {
+    link := &jira.IssueLink{Type: jira.IssueLinkType{Name: linkType}}
+    switch linkType {
+        case JIRA_LINK_BLOCKS:
+            link.Type.Outward, link.Type.Inward = "blocks", "is blocked by"
+        case JIRA_LINK_DUPLICATE:
+            link.Type.Outward, link.Type.Inward = "duplicates", "is duplicated by"
+        default:
+            link.Type.Outward, link.Type.Inward = "relates to", "relates to"
+    }
+    if outward {
+        link.OutwardIssue = &jira.Issue{Key: key}
+    } else {
+        link.InwardIssue = &jira.Issue{Key: key}
+    }
+    return link
+}
@@ -0,0 +1,109 @@
+// convert/milestone.go
+//
+// Conversion of Jira versions and epics to GitHub milestones.
+
+package convert
+
+import (
+	"strings"
+	"time"
+
+	"lib.virginia.edu/agita/markdown"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Translate a Jira version into a GitHub milestone.
+//  NOTE: a version which has been released or archived is closed.
+//  NOTE: the description ends with MilestoneTag().
+func VersionMilestone(version *Jira.Version) Github.Milestone {
+    released := (version.Released != nil) && *version.Released
+    archived := (version.Archived != nil) && *version.Archived
+    desc     := version.Description
+    if archived {
+        desc = appendParagraph(desc, "_(archived Jira version)_")
+    }
+    due, _ := time.Parse(time.DateOnly, version.ReleaseDate)
+    return Github.Milestone{
+        Title:       version.Name,
+        Description: appendParagraph(desc, MilestoneTag(VersionSource(version.Name))),
+        DueOn:       due,
+        Closed:      released || archived,
+    }
+}
+
+// Translate a Jira epic into a GitHub milestone.
+//  NOTE: the title is prefixed with the epic's key like the title of an issue.
+//  NOTE: an epic which has been resolved is closed.
+//  NOTE: the description ends with MilestoneTag().
+func EpicMilestone(epic Jira.Issue) Github.Milestone {
+    title := epic.Summary()
+    if title == "" {
+        title = "(no title)"
+    }
+    desc := markdown.JiraToGithub(epic.Description())
+    return Github.Milestone{
+        Title:       epic.Key() + " " + title,
+        Description: appendParagraph(desc, MilestoneTag(EpicSource(epic.Key()))),
+        DueOn:       Jira.AsTime(epic.Duedate()),
+        Closed:      epic.Resolution() != "",
+    }
+}
+
+// The line which marks the description of a milestone made from the given
+// Jira version or epic (e.g. "_(from Jira version 1.2)_").
+//  @see convert.MilestoneSource()
+func MilestoneTag(source string) string {
+    kind, name, _ := strings.Cut(source, ":")
+    return "_(from Jira " + kind + " " + name + ")_"
+}
+
+// Indicate whether the milestone was made from the given Jira version or epic
+// (as opposed to a milestone of the same title created on GitHub).
+func MadeFrom(milestone Github.Milestone, source string) bool {
+    return strings.Contains(milestone.Description, MilestoneTag(source))
+}
+
+// Identify the Jira version from which a milestone is made.
+func VersionSource(name string) string {
+    return "version:" + name
+}
+
+// Identify the Jira epic from which a milestone is made.
+func EpicSource(key Jira.IssueKey) string {
+    return "epic:" + key
+}
+
+// Identify the Jira version or epic of the milestone to which the issue should
+// be assigned: its first fix version or else its epic.
+//  NOTE: an epic is assigned to its own milestone if it has no fix version.
+//  NOTE: returns blank if the issue has neither.
+func MilestoneSource(issue Jira.Issue) string {
+    if versions := issue.FixVersions(); len(versions) > 0 {
+        return VersionSource(versions[0])
+    }
+    if epic := issue.Epic(); epic != "" {
+        return EpicSource(epic)
+    }
+    if issue.Type() == Jira.EPIC_TYPE {
+        return EpicSource(issue.Key())
+    }
+    return ""
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Add a paragraph to the end of the text.
+func appendParagraph(text, paragraph string) string {
+    if text == "" {
+        return paragraph
+    }
+    return text + "\n\n" + paragraph
+}
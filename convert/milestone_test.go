@@ -0,0 +1,138 @@
+// convert/milestone_test.go
+
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestVersionMilestone(t *testing.T) {
+    const fn = "VersionMilestone"
+
+	type testCase struct {
+		name    string
+		version *Jira.Version
+		want    Github.Milestone
+	}
+
+    yes, no := true, false
+    tag     := "_(from Jira version 1.2)_"
+    due     := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+    Case := func(idx int, released, archived *bool, desc, date string, want Github.Milestone) testCase {
+        version := &Jira.Version{
+            Name:        "1.2",
+            Description: desc,
+            Released:    released,
+            Archived:    archived,
+            ReleaseDate: date,
+        }
+        want.Title = version.Name
+        return testCase{test.CaseName(fn, idx), version, want}
+    }
+
+	tests := []testCase{
+        Case(0, nil, nil, "", "",
+                Github.Milestone{Description: tag}),
+        Case(1, &no, &no, "First", "2024-05-01",
+                Github.Milestone{Description: "First\n\n" + tag, DueOn: due}),
+        Case(2, &yes, &no, "First", "",
+                Github.Milestone{Description: "First\n\n" + tag, Closed: true}),
+        Case(3, &no, &yes, "First", "",
+                Github.Milestone{Description: "First\n\n_(archived Jira version)_\n\n" + tag, Closed: true}),
+        Case(4, &yes, &yes, "", "",
+                Github.Milestone{Description: "_(archived Jira version)_\n\n" + tag, Closed: true}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := VersionMilestone(tt.version)
+            if !got.Same(tt.want) {
+                t.Errorf("%s() = %+v, want %+v", fn, got, tt.want)
+            }
+            if !MadeFrom(got, VersionSource(tt.version.Name)) {
+                t.Errorf("%s() description %q lacks tag", fn, got.Description)
+            }
+		})
+	}
+}
+
+func TestEpicMilestone(t *testing.T) {
+    const fn = "EpicMilestone"
+
+	type testCase struct {
+		name       string
+		summary    string
+		resolution string
+		want       Github.Milestone
+	}
+
+    tag  := "_(from Jira epic LIBRA-5)_"
+    Case := func(idx int, summary, resolution string, want Github.Milestone) testCase {
+        want.Description = tag
+        return testCase{test.CaseName(fn, idx), summary, resolution, want}
+    }
+
+	tests := []testCase{
+        Case(0, "Search", "",
+                Github.Milestone{Title: "LIBRA-5 Search"}),
+        Case(1, "", "",
+                Github.Milestone{Title: "LIBRA-5 (no title)"}),
+        Case(2, "Search", "Done",
+                Github.Milestone{Title: "LIBRA-5 Search", Closed: true}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            fields := &jira.IssueFields{Summary: tt.summary}
+            if tt.resolution != "" {
+                fields.Resolution = &jira.Resolution{Name: tt.resolution}
+            }
+            epic := *Jira.NewIssueType(&Jira.Client{}, &jira.Issue{Key: "LIBRA-5", Fields: fields})
+            if got := EpicMilestone(epic); !got.Same(tt.want) {
+                t.Errorf("%s() = %+v, want %+v", fn, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestMadeFrom(t *testing.T) {
+    const fn = "MadeFrom"
+
+	type testCase struct {
+		name   string
+		desc   string
+		source string
+		want   bool
+	}
+
+    Case := func(idx int, desc, source string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), desc, source, want}
+    }
+
+	tests := []testCase{
+        Case(0, "Notes\n\n_(from Jira version 1.2)_", VersionSource("1.2"),    true),
+        Case(1, "_(from Jira epic LIBRA-5)_",        EpicSource("LIBRA-5"),   true),
+        Case(2, "_(from Jira version 1.2)_",         VersionSource("1.2.1"),  false),
+        Case(3, "_(from Jira version 1.2)_",         EpicSource("1.2"),       false),
+        Case(4, "Release 1.2 of the application",    VersionSource("1.2"),    false),
+        Case(5, "",                                  VersionSource("1.2"),    false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            milestone := Github.Milestone{Title: "1.2", Description: tt.desc}
+            if got := MadeFrom(milestone, tt.source); got != tt.want {
+                t.Errorf("%s(%q, %q) = %v, want %v", fn, tt.desc, tt.source, got, tt.want)
+            }
+		})
+	}
+}
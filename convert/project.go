@@ -5,6 +5,8 @@
 package convert
 
 import (
+	"strings"
+
 	"lib.virginia.edu/agita/log"
 	"lib.virginia.edu/agita/util"
 
@@ -70,6 +72,17 @@ func ProjectRepositoryFor(jiraKey string) string {
     return Github.PROJ_NAME_PREFIX + jiraKey
 }
 
+// Give the Jira project key associated with the given GitHub repository name.
+func ProjectFor(repo string) string {
+    if proj := RepoToProject[repo]; proj != "" {
+        return proj
+    }
+    if proj, ok := strings.CutPrefix(repo, Github.PROJ_NAME_PREFIX); ok {
+        return strings.ToUpper(proj)
+    }
+    return ""
+}
+
 // Render a Jira project into JSON.
 func ProjectToJson(src Jira.Project) string {
     if bytes, err := src.MarshalJSON(); log.ErrorValue(err) == nil {
@@ -0,0 +1,152 @@
+// convert/reference.go
+//
+// Conversion of references to Jira issues into links to GitHub issues.
+
+package convert
+
+import (
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/re"
+
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A function which gives the GitHub URL for a transferred Jira issue or, if
+// `comment` is not blank, for one of its comments.
+//  NOTE: returns blank if the Jira issue has not been transferred.
+type Resolver func(key Jira.IssueKey, comment string) string
+
+// ============================================================================
+// Internal constants
+// ============================================================================
+
+// Matches a code block, code span, Markdown link, HTML image or link tag, URL,
+// or Jira issue key, leftmost first, so that text within code, link text and
+// tag attributes is not changed.
+const referencePattern = "(?s:```.*?```)" +
+    "|`[^`\n]*`" +
+    `|\[[^\]\n]*\]\([^)\s]+\)` +
+    `|<(?:a|img)\s[^>\n]*>` +
+    `|https?://[^\s<>()\[\]"']+` +
+    `|\b[A-Z][A-Z0-9]*-[0-9]+\b`
+
+// Parts of a Markdown link.
+const linkPattern = `^(\[[^\]\n]*\]\()([^)\s]+)\)$`
+
+// Parts of a Jira browse URL path: the issue key and the rest of the URL.
+const browsePattern = `^([A-Z][A-Z0-9]*-[0-9]+)([?#].*)?$`
+
+// The Jira comment ID in the query or fragment of a browse URL.
+const commentIdPattern = `(?:focusedCommentId=|#comment-)([0-9]+)`
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Replace references to Jira issues with links to the GitHub issues to which
+// they were transferred.
+//
+// * A bare issue key like "LIBRA-512" becomes a Markdown link to the issue.
+// * A Jira browse URL becomes the URL of the GitHub issue.
+// * A Jira browse URL with "focusedCommentId" becomes the URL of the GitHub
+//   comment if its GitHub ID is known or of the GitHub issue otherwise.
+//
+// Only keys of projects in ProjectToRepo are treated as issue references.
+// The keys of referenced issues which could not be resolved are returned so
+// that the text can be fixed up after they have been transferred.
+//
+func References(text string, resolve Resolver) (string, []string) {
+    unresolved := []string{}
+    lookup := func(key, comment string) string {
+        url := resolve(key, comment)
+        if (url == "") && !slices.Contains(unresolved, key) {
+            unresolved = append(unresolved, key)
+        }
+        return url
+    }
+    repl := func(match string) string {
+        switch {
+            case strings.HasPrefix(match, "`"):
+                return match
+            case strings.HasPrefix(match, "["):
+                parts := re.FindAllStringSubmatch(match, linkPattern, 1)
+                if len(parts) == 0 {
+                    return match
+                }
+                if url := referenceURL(parts[0][2], lookup); url != "" {
+                    return parts[0][1] + url + ")"
+                }
+                return match
+            case strings.HasPrefix(match, "http"):
+                link, trail := trimTrailing(match)
+                if url := referenceURL(link, lookup); url != "" {
+                    return url + trail
+                }
+                return match
+            case IsIssueKey(match):
+                if url := lookup(match, ""); url != "" {
+                    return "[" + match + "](" + url + ")"
+                }
+                return match
+            default:
+                return match
+        }
+    }
+    return re.ReplaceAllFunc(text, referencePattern, repl), unresolved
+}
+
+// Indicate whether the string is the key of an issue of a known Jira project.
+func IsIssueKey(key string) bool {
+    proj, num, found := strings.Cut(key, "-")
+    if !found || (num == "") {
+        return false
+    }
+    _, known := ProjectToRepo[proj]
+    return known
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// If `link` is a Jira browse URL, return the GitHub URL for the issue or
+// comment it refers to.
+//  NOTE: returns blank if `link` is not a Jira browse URL or if the referenced
+//  issue has not been transferred.
+func referenceURL(link string, lookup Resolver) string {
+    path := ""
+    for _, base := range jiraBrowseURLs() {
+        if rest, ok := strings.CutPrefix(link, base); ok {
+            path = rest
+            break
+        }
+    }
+    parts := re.FindAllStringSubmatch(path, browsePattern, 1)
+    if (len(parts) == 0) || !IsIssueKey(parts[0][1]) {
+        return ""
+    }
+    key, comment := parts[0][1], ""
+    if ids := re.FindAllStringSubmatch(parts[0][2], commentIdPattern, 1); len(ids) > 0 {
+        comment = ids[0][1]
+    }
+    return lookup(key, comment)
+}
+
+// The prefixes of Jira browse URLs.
+func jiraBrowseURLs() []string {
+    https := strings.TrimSuffix(Jira.BASE_URL, "/") + "/browse/"
+    http  := strings.Replace(https, "https://", "http://", 1)
+    return []string{https, http}
+}
+
+// Separate sentence punctuation which was matched at the end of a URL.
+func trimTrailing(link string) (string, string) {
+    trimmed := strings.TrimRight(link, ".,;:!?")
+    return trimmed, link[len(trimmed):]
+}
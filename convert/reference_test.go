@@ -0,0 +1,143 @@
+// convert/reference_test.go
+
+package convert
+
+import (
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestReferences(t *testing.T) {
+    const fn = "References"
+
+	type testCase struct {
+		name       string
+		text       string
+		want       string
+		unresolved []string
+	}
+
+    const (
+        issueURL   = "https://github.com/uvalib/Libra2/issues/7"
+        commentURL = issueURL + "#issuecomment-99"
+        browse     = Jira.BASE_URL + "browse/"
+    )
+    resolve := func(key Jira.IssueKey, comment string) string {
+        switch {
+            case key != "LIBRA-1":  return ""
+            case comment == "10001": return commentURL
+            default:                return issueURL
+        }
+    }
+    Case := func(idx int, text, want string, unresolved ...string) testCase {
+        return testCase{test.CaseName(fn, idx), text, want, unresolved}
+    }
+
+	tests := []testCase{
+        Case(0, "See LIBRA-1.",
+                "See [LIBRA-1](" + issueURL + ")."),
+        Case(1, "LIBRA-2 and LIBRA-2 and EMMA-3",
+                "LIBRA-2 and LIBRA-2 and EMMA-3", "LIBRA-2", "EMMA-3"),
+        Case(2, "ABC-1 and UTF-8 are not keys",
+                "ABC-1 and UTF-8 are not keys"),
+        Case(3, "`LIBRA-1` and\n```\nLIBRA-1\n```",
+                "`LIBRA-1` and\n```\nLIBRA-1\n```"),
+        Case(4, "At " + browse + "LIBRA-1, done",
+                "At " + issueURL + ", done"),
+        Case(5, browse + "LIBRA-1?focusedCommentId=10001&page=x",
+                commentURL),
+        Case(6, browse + "LIBRA-1#comment-10002",
+                issueURL),
+        Case(7, "[the issue](" + browse + "LIBRA-1)",
+                "[the issue](" + issueURL + ")"),
+        Case(8, "[LIBRA-1](https://example.com/LIBRA-1)",
+                "[LIBRA-1](https://example.com/LIBRA-1)"),
+        Case(9, `<a href="` + browse + `LIBRA-1">LIBRA-1</a>`,
+                `<a href="` + browse + `LIBRA-1">[LIBRA-1](` + issueURL + `)</a>`),
+        Case(10, browse + "LIBRA-5",
+                 browse + "LIBRA-5", "LIBRA-5"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got, unresolved := References(tt.text, resolve)
+            if got != tt.want {
+                t.Errorf("%s() = %q, want %q", fn, got, tt.want)
+            }
+            if want := append([]string{}, tt.unresolved...); !slices.Equal(unresolved, want) {
+                t.Errorf("%s() unresolved = %q, want %q", fn, unresolved, want)
+            }
+		})
+	}
+}
+
+func TestIsIssueKey(t *testing.T) {
+    const fn = "IsIssueKey"
+
+	type testCase struct {
+		name string
+		key  string
+		want bool
+	}
+
+    Case := func(idx int, key string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), key, want}
+    }
+
+	tests := []testCase{
+        Case(0, "LIBRA-512", true),
+        Case(1, "CSH-1",     true),
+        Case(2, "ABC-1",     false),
+        Case(3, "LIBRA-",    false),
+        Case(4, "LIBRA",     false),
+        Case(5, "",          false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := IsIssueKey(tt.key); got != tt.want {
+                t.Errorf("%s(%q) = %v, want %v", fn, tt.key, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestTrimTrailing(t *testing.T) {
+    const fn = "trimTrailing"
+
+	type testCase struct {
+		name  string
+		link  string
+		want  string
+		trail string
+	}
+
+    Case := func(idx int, link, want, trail string) testCase {
+        return testCase{test.CaseName(fn, idx), link, want, trail}
+    }
+
+	tests := []testCase{
+        Case(0, "https://host/a",     "https://host/a", ""),
+        Case(1, "https://host/a.",    "https://host/a", "."),
+        Case(2, "https://host/a?!",   "https://host/a", "?!"),
+        Case(3, "https://host/a.b",   "https://host/a.b", ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got, trail := trimTrailing(tt.link)
+            if (got != tt.want) || (trail != tt.trail) {
+                t.Errorf("%s(%q) = (%q, %q), want (%q, %q)", fn, tt.link, got, trail, tt.want, tt.trail)
+            }
+		})
+	}
+}
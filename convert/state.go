@@ -0,0 +1,71 @@
+// convert/state.go
+//
+// Conversion of Jira status and resolution to GitHub issue state.
+
+package convert
+
+import (
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// GitHub issue state reasons for a closed issue.
+const (
+    STATE_COMPLETED   = "COMPLETED"
+    STATE_NOT_PLANNED = "NOT_PLANNED"
+    STATE_DUPLICATE   = "DUPLICATE"
+)
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// Indicates whether an issue in a Jira status category is closed on GitHub.
+// An issue whose status category does not appear here is closed if it has a
+// resolution.
+var StatusCategoryClosed = map[string]bool {
+    "new":              false,  // "To Do"
+    "indeterminate":    false,  // "In Progress"
+    "done":             true,   // "Done"
+}
+
+// A mapping of Jira resolution to the GitHub state reason of a closed issue.
+// A resolution which does not appear here is treated as STATE_COMPLETED.
+var ResolutionToStateReason = map[string]string {
+    "Fixed":            STATE_COMPLETED,
+    "Done":             STATE_COMPLETED,
+    "Won't Fix":        STATE_NOT_PLANNED,
+    "Won't Do":         STATE_NOT_PLANNED,
+    "Cannot Reproduce": STATE_NOT_PLANNED,
+    "Incomplete":       STATE_NOT_PLANNED,
+    "Obsolete":         STATE_NOT_PLANNED,
+    "Declined":         STATE_NOT_PLANNED,
+    "Duplicate":        STATE_DUPLICATE,
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Indicate whether the GitHub issue for the Jira issue should be closed.
+func Closed(issue Jira.Issue) bool {
+    if closed, known := StatusCategoryClosed[issue.StatusCategory()]; known {
+        return closed
+    }
+    return issue.Resolution() != ""
+}
+
+// The GitHub state reason for the Jira issue.
+//  NOTE: returns blank if the issue is not closed.
+func StateReason(issue Jira.Issue) string {
+    if !Closed(issue) {
+        return ""
+    }
+    if reason := ResolutionToStateReason[issue.Resolution()]; reason != "" {
+        return reason
+    }
+    return STATE_COMPLETED
+}
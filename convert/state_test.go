@@ -0,0 +1,76 @@
+// convert/state_test.go
+
+package convert
+
+import (
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Jira"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestStateReason(t *testing.T) {
+    const fn = "StateReason"
+
+	type testCase struct {
+		name       string
+		category   string
+		resolution string
+		closed     bool
+		want       string
+	}
+
+    Case := func(idx int, category, resolution string, closed bool, want string) testCase {
+        return testCase{test.CaseName(fn, idx), category, resolution, closed, want}
+    }
+
+	tests := []testCase{
+        Case(0,  "done",          "Done",             true,  STATE_COMPLETED),
+        Case(1,  "done",          "Fixed",            true,  STATE_COMPLETED),
+        Case(2,  "done",          "Won't Fix",        true,  STATE_NOT_PLANNED),
+        Case(3,  "done",          "Won't Do",         true,  STATE_NOT_PLANNED),
+        Case(4,  "done",          "Cannot Reproduce", true,  STATE_NOT_PLANNED),
+        Case(5,  "done",          "Duplicate",        true,  STATE_DUPLICATE),
+        Case(6,  "done",          "Other",            true,  STATE_COMPLETED),
+        Case(7,  "done",          "",                 true,  STATE_COMPLETED),
+        Case(8,  "indeterminate", "Fixed",            false, ""),
+        Case(9,  "new",           "",                 false, ""),
+        Case(10, "",              "Won't Fix",        true,  STATE_NOT_PLANNED),
+        Case(11, "",              "",                 false, ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            issue := testStateIssue(tt.category, tt.resolution)
+            if got := Closed(issue); got != tt.closed {
+                t.Errorf("Closed() = %v, want %v", got, tt.closed)
+            }
+            if got := StateReason(issue); got != tt.want {
+                t.Errorf("%s() = %q, want %q", fn, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Internal functions - test support
+// ============================================================================
+
+// A Jira issue with the given status category key and resolution (either of
+// which may be blank).
+func testStateIssue(category, resolution string) Jira.Issue {
+    fields := &jira.IssueFields{}
+    if category != "" {
+        fields.Status = &jira.Status{StatusCategory: jira.StatusCategory{Key: category}}
+    }
+    if resolution != "" {
+        fields.Resolution = &jira.Resolution{Name: resolution}
+    }
+    return *Jira.NewIssueType(&Jira.Client{}, &jira.Issue{Key: "TEST-1", Fields: fields})
+}
@@ -20,6 +20,7 @@ import (
 func From(value any) (result any, useable bool) {
     use := false
     switch v := value.(type) {
+        case bool:       use = v
         case int:        use = (v != 0)
         case string:     use = (v != "")
         case Jira.Time:  if Jira.ValidTime(v) { value, use = FromTime(v) }
@@ -0,0 +1,92 @@
+// convert/worklog.go
+//
+// Conversion of Jira worklogs and time tracking into GitHub issue content.
+
+package convert
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/log"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// The heading of a comment listing the time logged against a Jira issue.
+const WORKLOG_HEADING = "Jira worklog"
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Render a worklog record as JSON.
+func WorklogToJson(src Jira.Worklog) string {
+    if bytes, err := json.Marshal(src); log.ErrorValue(err) == nil {
+        return string(bytes)
+    } else {
+        return ""
+    }
+}
+
+// Render the worklog records of an issue as one or more comments containing
+// a table of the time logged in chronological order, ending with the total.
+// Each comment has the start time of its last record as its creation time.
+//  NOTE: returns an empty result if there are no worklog records.
+func Worklog(worklogs []Jira.Worklog) []*Github.CommentImport {
+    if len(worklogs) == 0 {
+        return []*Github.CommentImport{}
+    }
+    worklogs = slices.Clone(worklogs)
+    slices.SortStableFunc(worklogs, func(a, b Jira.Worklog) int {
+        return Github.MakeTime(a.Started).Compare(Github.MakeTime(b.Started).Time)
+    })
+    rows  := make([]string, 0, len(worklogs) + 1)
+    times := make([]string, 0, len(worklogs) + 1)
+    total := 0
+    for _, worklog := range worklogs {
+        rows  = append(rows, worklogRow(worklog))
+        times = append(times, worklog.Started)
+        total += worklog.Seconds
+    }
+    rows  = append(rows, "|*Total*| |*" + Jira.DurationString(total) + "*| |")
+    times = append(times, times[len(times)-1])
+    return tableComments(WORKLOG_HEADING, "||Date||Author||Duration||Comment||", rows, times)
+}
+
+// Render the time tracking values of the issue as a string for an annotation,
+// including the value for the issue and its sub-tasks if that is different.
+//  NOTE: returns an empty string if neither value is set.
+func TimeTracking(seconds, aggregate int) string {
+    switch {
+        case (seconds == 0) && (aggregate == 0):
+            return ""
+        case (aggregate == 0) || (aggregate == seconds):
+            return Jira.DurationString(seconds)
+        default:
+            with := Jira.DurationString(aggregate)
+            return Jira.DurationString(seconds) + " (" + with + " with sub-tasks)"
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Render a worklog record as a row of a Jira table.
+func worklogRow(worklog Jira.Worklog) string {
+    date     := Github.MakeTime(worklog.Started).Format("2006-01-02 15:04")
+    author   := Jira.AppendFullName(worklog.Author)
+    duration := Jira.DurationString(worklog.Seconds)
+    cells    := []string{date, author, duration, worklog.Comment}
+    for idx, cell := range cells {
+        cells[idx] = tableCell(cell)
+    }
+    return "|" + strings.Join(cells, "|") + "|"
+}
@@ -0,0 +1,148 @@
+// convert/worklog_test.go
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestWorklog(t *testing.T) {
+    const fn = "Worklog"
+
+    const author = "xyz9z" // Not in the user directory.
+    worklogs := []Jira.Worklog{
+        {Author: author, Started: "2024-03-02T13:00:00.000-0500", Seconds: 1800, Comment: "review |\n fixes"},
+        {Author: author, Started: "2024-03-01T09:30:00.000-0500", Seconds: 5400},
+    }
+    if got := Worklog(nil); len(got) != 0 {
+        t.Errorf("%s(nil) = %d comments, want 0", fn, len(got))
+    }
+    got := Worklog(worklogs)
+    if len(got) != 1 {
+        t.Fatalf("%s() = %d comments, want 1", fn, len(got))
+    }
+    want := strings.Join([]string{
+        "**" + WORKLOG_HEADING + "**",
+        "",
+        "| **Date** | **Author** | **Duration** | **Comment** |",
+        "| --- | --- | --- | --- |",
+        "| 2024-03-01 09:30 | " + author + " | 1h 30m |  |",
+        "| 2024-03-02 13:00 | " + author + " | 30m | review &#124; fixes |",
+        "| **Total** |  | **2h** |  |",
+    }, "\n")
+    if got[0].Body != want {
+        t.Errorf("%s() body = %q, want %q", fn, got[0].Body, want)
+    }
+    if at, want := got[0].CreatedAt, Github.MakeTime(worklogs[0].Started); (at == nil) || !at.Equal(want) {
+        t.Errorf("%s() created at %v, want %v", fn, at, want)
+    }
+    if worklogs[0].Seconds != 1800 {
+        t.Errorf("%s() reordered its argument", fn)
+    }
+}
+
+func TestTimeTracking(t *testing.T) {
+    const fn = "TimeTracking"
+
+	type testCase struct {
+		name      string
+		seconds   int
+		aggregate int
+		want      string
+	}
+
+    Case := func(idx int, seconds, aggregate int, want string) testCase {
+        return testCase{test.CaseName(fn, idx), seconds, aggregate, want}
+    }
+
+	tests := []testCase{
+        Case(0, 0,    0,    ""),
+        Case(1, 3600, 0,    "1h"),
+        Case(2, 3600, 3600, "1h"),
+        Case(3, 3600, 5400, "1h (1h 30m with sub-tasks)"),
+        Case(4, 0,    60,   "0m (1m with sub-tasks)"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := TimeTracking(tt.seconds, tt.aggregate); got != tt.want {
+                t.Errorf("%s(%d, %d) = %q, want %q", fn, tt.seconds, tt.aggregate, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestTableComments(t *testing.T) {
+    const fn = "tableComments"
+
+    if got := tableComments("Heading", "||A||", nil, nil); len(got) != 0 {
+        t.Errorf("%s() without rows = %d comments, want 0", fn, len(got))
+    }
+    row   := "|" + strings.Repeat("x", TABLE_COMMENT_MAX / 2) + "|"
+    rows  := []string{row, row, "|last|"}
+    times := []string{
+        "2024-01-01T00:00:00.000+0000",
+        "2024-01-02T00:00:00.000+0000",
+        "2024-01-03T00:00:00.000+0000",
+    }
+    got := tableComments("Heading", "||A||", rows, times)
+    if len(got) != 2 {
+        t.Fatalf("%s() = %d comments, want 2", fn, len(got))
+    }
+    for idx, comment := range got {
+        title := fmt.Sprintf("**Heading (%d of 2)**\n", idx + 1)
+        if !strings.HasPrefix(comment.Body, title) {
+            t.Errorf("%s()[%d] body starts %q, want %q", fn, idx, comment.Body[:30], title)
+        }
+    }
+    if !strings.HasSuffix(got[1].Body, "| last |") {
+        t.Errorf("%s()[1] body does not end with the last row", fn)
+    }
+    if at := got[1].CreatedAt; (at == nil) || (at.Day() != 3) {
+        t.Errorf("%s()[1] created at %v, want the time of its last row", fn, at)
+    }
+}
+
+func TestTableCell(t *testing.T) {
+    const fn = "tableCell"
+
+	type testCase struct {
+		name  string
+		value string
+		want  string
+	}
+
+    Case := func(idx int, value, want string) testCase {
+        return testCase{test.CaseName(fn, idx), value, want}
+    }
+
+    long  := strings.Repeat("é", TABLE_VALUE_MAX + 1)
+    tests := []testCase{
+        Case(0, "plain",            "plain"),
+        Case(1, "  two\n lines  ",  "two lines"),
+        Case(2, "a|b",              "a&#124;b"),
+        Case(3, "",                 " "),
+        Case(4, long,               long[:len(long) - len("é")] + "..."),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := tableCell(tt.value); got != tt.want {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.value, got, tt.want)
+            }
+		})
+	}
+}
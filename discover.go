@@ -0,0 +1,115 @@
+// discover.go
+//
+// Discovery of the GitHub accounts of the people involved in Jira projects.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"lib.virginia.edu/agita/users"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+// Path relative to project root of the file of proposed user directory
+// entries written by DiscoverAll.
+const DISCOVER_FILE = "tmp/users-proposed.csv"
+
+// Proposals with a lower confidence are counted as needing review.
+const DISCOVER_REVIEW = 80
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Find every reporter, creator, assignee, commenter and watcher of issues in
+// the given projects and write DISCOVER_FILE with a proposed user directory
+// entry for each, matching their Jira profile with the GitHub accounts of
+// members of the organization.
+//
+//  NOTE: projectKeys must have ALL_PROJECTS or a list of Jira project keys.
+func DiscoverAll(projectKeys ...string) {
+    projIssues := ValidateProjectKeys(projectKeys...)
+    projectKeys = util.MapKeys(projIssues)
+    all  := slices.Contains(projectKeys, ALL_PROJECTS)
+    seen := map[string]string{}
+    for _, project := range Jira.MainClient().GetProjects() {
+        if proj := project.Key(); all || slices.Contains(projectKeys, proj) {
+            minMax := []string{}
+            if !all {
+                minMax = projIssues[proj]
+            }
+            for account, key := range projectAccounts(project, minMax, true) {
+                if _, found := seen[account]; !found {
+                    seen[account] = key
+                }
+            }
+        }
+    }
+    accounts := util.MapKeys(seen)
+    slices.Sort(accounts)
+
+    candidates := githubCandidates()
+    proposals  := make([]*users.Proposal, 0, len(accounts))
+    review     := 0
+    for _, account := range accounts {
+        user := users.User{Jira: account}
+        if profile := Jira.GetUserProfile(nil, account); profile == nil {
+            logWarning("DISCOVER: no Jira profile for %s (e.g. %s)", account, seen[account])
+            user.Status = users.STATUS_INACTIVE
+        } else {
+            user.Name, user.Email = profile.Name, profile.Email
+            if !profile.Active {
+                user.Status = users.STATUS_INACTIVE
+            }
+        }
+        proposal := users.Propose(user, candidates)
+        if proposal.Confidence < DISCOVER_REVIEW {
+            review++
+        }
+        proposals = append(proposals, proposal)
+    }
+
+    path := filepath.Join(util.RootPath(), DISCOVER_FILE)
+    err  := os.MkdirAll(filepath.Dir(path), 0755)
+    if err == nil {
+        err = users.WriteProposals(path, proposals)
+    }
+    if err != nil {
+        logError("DISCOVER: %v", err)
+        return
+    }
+    logSummary("DISCOVER: %d JIRA ACCOUNTS, %d GITHUB MEMBERS, %d TO REVIEW -> %s", len(accounts), len(candidates), review, path)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The GitHub accounts of members of the organization with the names and email
+// addresses which may identify their owners.
+//  NOTE: verified domain emails are only available with an organization owner
+//  token.
+func githubCandidates() []users.Candidate {
+    client   := Github.MainClient()
+    verified := Github.GqlVerifiedEmails(Github.ORG)
+    logins   := Github.OrgMembers(client, Github.ORG)
+    res      := make([]users.Candidate, 0, len(logins))
+    for _, login := range logins {
+        candidate := users.Candidate{Login: login, Verified: verified[login]}
+        if user := Github.GetUser(client, login); user != nil {
+            candidate.Name, candidate.Email = user.Name(), user.Email()
+        }
+        res = append(res, candidate)
+    }
+    return res
+}
@@ -22,12 +22,14 @@ import (
 const PROJECTS_KEY = "Projects"
 const ISSUES_KEY   = "Issues"
 const COMMENTS_KEY = "Comments"
+const WORKLOGS_KEY = "Worklogs"
+const CUSTOM_KEY   = "CustomFields"
 
 // ============================================================================
 // Functions
 // ============================================================================
 
-// Output JSON for all projects and their issues and comments.
+// Output JSON for all projects and their issues, comments and worklogs.
 //  NOTE: projectKeys must have ALL_PROJECTS or a list of Jira project keys.
 //  NOTE: ignores issue range specifications
 func ExportAll(projectKeys ...string) {
@@ -68,9 +70,16 @@ func ProjectJson(project *Jira.Project) string {
     return result
 }
 
-// Return JSON for the issue object and its comments.
+// Return JSON for the issue object, its custom fields, and its comments and
+// worklog records.
+//  NOTE: custom fields are given by name and those mapped to be dropped by
+//  convert.FieldMap are not included.
 func IssueJson(jiraIssue Jira.Issue) string {
     result := convert.IssueToJson(jiraIssue)
+    if custom := convert.CustomFieldsToJson(jiraIssue); custom != "" {
+        result = strings.TrimSuffix(result, "}")
+        result += fmt.Sprintf(",\n%q: %s}", CUSTOM_KEY, custom)
+    }
     items  := jiraIssue.Comments()
     if len(items) > 0 {
         result = strings.TrimSuffix(result, "}")
@@ -80,6 +89,15 @@ func IssueJson(jiraIssue Jira.Issue) string {
         }
         result = strings.TrimSuffix(result, ",\n") + "\n]}"
     }
+    worklogs := jiraIssue.Worklogs()
+    if len(worklogs) > 0 {
+        result = strings.TrimSuffix(result, "}")
+        result += fmt.Sprintf(",\n%q: [\n", WORKLOGS_KEY)
+        for _, worklog := range worklogs {
+            result += WorklogJson(worklog) + ",\n"
+        }
+        result = strings.TrimSuffix(result, ",\n") + "\n]}"
+    }
     return result
 }
 
@@ -87,3 +105,8 @@ func IssueJson(jiraIssue Jira.Issue) string {
 func CommentJson(arg Jira.Comment) string {
     return convert.CommentToJson(arg)
 }
+
+// Return JSON for the worklog record.
+func WorklogJson(arg Jira.Worklog) string {
+    return convert.WorklogToJson(arg)
+}
@@ -0,0 +1,74 @@
+// label.go
+//
+// GitHub labels synthesized from Jira issue fields.
+//
+// Labels generated according to convert.LABEL_SCHEMES are created in the
+// target repository as needed before the issue which uses them is imported.
+// A label with the same name (ignoring case) which already exists in the
+// repository is used as-is; this allows a mapped repository like "emma" to
+// keep its own colors and descriptions for labels which it already has.
+
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"lib.virginia.edu/agita/Github"
+)
+
+// ============================================================================
+// Variables
+// ============================================================================
+
+// For each repository, its labels by lowercase name.
+var repoLabels = map[string]map[string]Github.Label{}
+
+// Existing labels which were used in place of synthesized labels, by
+// "repo:name", so that each collision is only reported once.
+var labelCollisions = map[string]bool{}
+
+// Guards access to repoLabels and labelCollisions.
+var repoLabelsMutex sync.Mutex
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Create the synthesized labels which do not yet exist in the repository, and
+// give the label names for an issue as they are spelled in the repository.
+//  NOTE: must be run in the GitHub writer stage.
+func prepareLabels(repo string, names []string, synthesized []Github.Label) []string {
+    if len(names) == 0 {
+        return names
+    }
+    repoLabelsMutex.Lock()
+    defer repoLabelsMutex.Unlock()
+    client   := Github.MainClient()
+    existing := repoLabels[repo]
+    if existing == nil {
+        existing = Github.GetLabels(client, Github.ORG, repo)
+        repoLabels[repo] = existing
+    }
+    for _, label := range synthesized {
+        lower := strings.ToLower(label.Name)
+        if current, found := existing[lower]; found {
+            if (current.Description != label.Description) && !labelCollisions[repo + ":" + lower] {
+                logWarning("%s: USING EXISTING LABEL %q", repo, current.Name)
+                labelCollisions[repo + ":" + lower] = true
+            }
+        } else if Github.CreateLabel(client, Github.ORG, repo, label) {
+            existing[lower] = label
+        } else {
+            logError("%s: LABEL %q NOT CREATED", repo, label.Name)
+        }
+    }
+    res := make([]string, 0, len(names))
+    for _, name := range names {
+        if current, found := existing[strings.ToLower(name)]; found {
+            name = current.Name
+        }
+        res = append(res, name)
+    }
+    return res
+}
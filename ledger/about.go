@@ -0,0 +1,4 @@
+// ledger/about.go
+
+// Persistent record of Jira issues transferred to GitHub.
+package ledger
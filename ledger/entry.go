@@ -0,0 +1,189 @@
+// ledger/entry.go
+//
+// The ledger record for a single Jira issue.
+
+package ledger
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// The transfer state of a Jira issue.
+type Status = string
+
+// Transfer state values.
+const (
+    StatusNone     Status = ""
+    StatusPending  Status = "pending"   // Import submitted but not confirmed.
+    StatusImported Status = "imported"  // GitHub issue created.
+    StatusFailed   Status = "failed"    // Import (or submission) failed.
+)
+
+// The kind of a GitHub relationship between transferred issues.
+type LinkKind = string
+
+// GitHub relationship kinds.
+const (
+    LinkBlockedBy   LinkKind = "blocked_by"     // Issue dependency.
+    LinkDuplicateOf LinkKind = "duplicate_of"   // Closed as a duplicate.
+    LinkSubIssueOf  LinkKind = "sub_issue_of"   // Sub-issue of a parent issue.
+)
+
+// A GitHub relationship from the issue to another transferred issue.
+type Link struct {
+    Kind    LinkKind    `json:"kind"`
+    Key     string      `json:"key"`
+    Created bool        `json:"created,omitempty"`
+}
+
+// The name of a GitHub issue property which is applied after import.
+type PropertyName = string
+
+// GitHub issue property names.
+const (
+    PropertyIssueType   PropertyName = "issue_type"     // GitHub issue type name.
+    PropertyStateReason PropertyName = "state_reason"   // Reason a closed issue was closed.
+)
+
+// A GitHub issue property which cannot be given in an import request.
+type Property struct {
+    Name    PropertyName    `json:"name"`
+    Value   string          `json:"value"`
+    Applied bool            `json:"applied,omitempty"`
+}
+
+// The ledger record of the transfer of a single Jira issue.
+type Entry struct {
+    Key         string              `json:"key"`
+    Status      Status              `json:"status"`
+    ImportID    int                 `json:"import_id,omitempty"`
+    Issue       int                 `json:"issue,omitempty"`
+    Attachments []string            `json:"attachments,omitempty"`
+    Comments    map[string]int64    `json:"comments,omitempty"`  // Jira ID (or table comment key) to GitHub ID
+    Unresolved  []string            `json:"unresolved,omitempty"`  // Jira keys referenced but not yet transferred
+    Links       []Link              `json:"links,omitempty"`
+    Properties  []Property          `json:"properties,omitempty"`
+    Hash        string              `json:"hash,omitempty"`
+    Error       string              `json:"error,omitempty"`
+    Updated     time.Time           `json:"updated"`
+}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Order Jira issue keys by project and then numerically by issue number so
+// that "PROJ-9" comes before "PROJ-10".
+func CompareKeys(a, b string) int {
+    aProj, aNum := splitKey(a)
+    bProj, bNum := splitKey(b)
+    if res := cmp.Compare(aProj, bProj); res != 0 {
+        return res
+    }
+    return cmp.Compare(aNum, bNum)
+}
+
+// ============================================================================
+// Exported members
+// ============================================================================
+
+// Indicate whether the issue has been completely transferred.
+func (e *Entry) Done() bool {
+    return (e != nil) && (e.Status == StatusImported)
+}
+
+// Indicate whether the issue import was submitted but not yet confirmed.
+func (e *Entry) Pending() bool {
+    return (e != nil) && (e.Status == StatusPending) && (e.ImportID != 0)
+}
+
+// Indicate whether the issue transfer was attempted and failed.
+func (e *Entry) Failed() bool {
+    return (e != nil) && (e.Status == StatusFailed)
+}
+
+// The GitHub comment ID for the given Jira comment ID, or 0 if not known.
+func (e *Entry) Comment(jiraID string) int64 {
+    if e == nil { return 0 }
+    return e.Comments[jiraID]
+}
+
+// The GitHub relationships which have not yet been created.
+func (e *Entry) PendingLinks() []Link {
+    res := []Link{}
+    if e != nil {
+        for _, link := range e.Links {
+            if !link.Created {
+                res = append(res, link)
+            }
+        }
+    }
+    return res
+}
+
+// The GitHub issue properties which have not yet been applied.
+func (e *Entry) PendingProperties() []Property {
+    res := []Property{}
+    if e != nil {
+        for _, prop := range e.Properties {
+            if !prop.Applied {
+                res = append(res, prop)
+            }
+        }
+    }
+    return res
+}
+
+// Indicate whether the given attachment file has already been stored.
+func (e *Entry) HasAttachment(file string) bool {
+    return (e != nil) && slices.Contains(e.Attachments, file)
+}
+
+// ============================================================================
+// Internal members
+// ============================================================================
+
+// Return a copy of the instance which does not share slices.
+func (e *Entry) clone() *Entry {
+    if e == nil { return nil }
+    res := *e
+    res.Attachments = slices.Clone(e.Attachments)
+    res.Comments    = maps.Clone(e.Comments)
+    res.Unresolved  = slices.Clone(e.Unresolved)
+    res.Links       = slices.Clone(e.Links)
+    res.Properties  = slices.Clone(e.Properties)
+    return &res
+}
+
+// Indicate whether the two links describe the same relationship.
+func (l Link) same(other Link) bool {
+    return (l.Kind == other.Kind) && (l.Key == other.Key)
+}
+
+// Indicate whether the two properties have the same name and value.
+func (p Property) same(other Property) bool {
+    return (p.Name == other.Name) && (p.Value == other.Value)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Separate a Jira issue key into project key and issue number.
+func splitKey(key string) (string, int) {
+    idx := strings.LastIndex(key, "-")
+    if idx < 0 {
+        return key, 0
+    }
+    num, _ := strconv.Atoi(key[idx+1:])
+    return key[:idx], num
+}
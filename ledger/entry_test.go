@@ -0,0 +1,117 @@
+// ledger/entry_test.go
+
+package ledger
+
+import (
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestCompareKeys(t *testing.T) {
+    const fn = "CompareKeys"
+
+	type args struct {
+		a string
+		b string
+	}
+	type testCase struct {
+		name string
+		args args
+		want int
+	}
+
+    Case := func(idx int, a, b string, want int) testCase {
+        return testCase{test.CaseName(fn, idx), args{a, b}, want}
+    }
+
+	tests := []testCase{
+        Case(0, "PROJ-9",  "PROJ-10", -1),
+        Case(1, "PROJ-10", "PROJ-9",  1),
+        Case(2, "PROJ-10", "PROJ-10", 0),
+        Case(3, "ABC-99",  "PROJ-1",  -1),
+        Case(4, "PROJ",    "PROJ-1",  -1),
+        Case(5, "A-B-2",   "A-B-10",  -1),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := CompareKeys(tt.args.a, tt.args.b); got != tt.want {
+                t.Errorf("%s(%q, %q) = %d, want %d", fn, tt.args.a, tt.args.b, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Tests - Exported members
+// ============================================================================
+
+func TestEntryStatus(t *testing.T) {
+    const fn = "Entry"
+
+	type testCase struct {
+		name    string
+		entry   *Entry
+		done    bool
+		pending bool
+		failed  bool
+	}
+
+    Case := func(idx int, entry *Entry, done, pending, failed bool) testCase {
+        return testCase{test.CaseName(fn, idx), entry, done, pending, failed}
+    }
+
+	tests := []testCase{
+        Case(0, nil,                                            false, false, false),
+        Case(1, &Entry{},                                       false, false, false),
+        Case(2, &Entry{Status: StatusImported, Issue: 1},       true,  false, false),
+        Case(3, &Entry{Status: StatusPending, ImportID: 1},     false, true,  false),
+        Case(4, &Entry{Status: StatusPending},                  false, false, false),
+        Case(5, &Entry{Status: StatusFailed, Error: "failed"},  false, false, true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := tt.entry.Done(); got != tt.done {
+                t.Errorf("%s.Done() = %v, want %v", fn, got, tt.done)
+            }
+            if got := tt.entry.Pending(); got != tt.pending {
+                t.Errorf("%s.Pending() = %v, want %v", fn, got, tt.pending)
+            }
+            if got := tt.entry.Failed(); got != tt.failed {
+                t.Errorf("%s.Failed() = %v, want %v", fn, got, tt.failed)
+            }
+		})
+	}
+}
+
+func TestEntryPending(t *testing.T) {
+    const fn = "Entry"
+
+    entry := &Entry{
+        Links: []Link{
+            {Kind: LinkBlockedBy,   Key: "PROJ-1", Created: true},
+            {Kind: LinkSubIssueOf,  Key: "PROJ-2"},
+        },
+        Properties: []Property{
+            {Name: PropertyIssueType,   Value: "Bug", Applied: true},
+            {Name: PropertyStateReason, Value: "completed"},
+        },
+    }
+    if got := entry.PendingLinks(); (len(got) != 1) || (got[0].Key != "PROJ-2") {
+        t.Errorf("%s.PendingLinks() = %v, want only PROJ-2", fn, got)
+    }
+    if got := entry.PendingProperties(); (len(got) != 1) || (got[0].Name != PropertyStateReason) {
+        t.Errorf("%s.PendingProperties() = %v, want only %q", fn, got, PropertyStateReason)
+    }
+    var none *Entry
+    if got := none.PendingLinks(); (got == nil) || (len(got) != 0) {
+        t.Errorf("nil %s.PendingLinks() = %v, want empty", fn, got)
+    }
+    if got := none.Comment("10001"); got != 0 {
+        t.Errorf("nil %s.Comment() = %d, want 0", fn, got)
+    }
+}
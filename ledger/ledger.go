@@ -0,0 +1,472 @@
+// ledger/ledger.go
+//
+// A durable per-project record of Jira issues transferred to GitHub.
+//
+// Each project ledger is kept as a journal of JSON lines, one line per entry
+// update, so that an interrupted transfer loses at most the update which was
+// in progress.  When a ledger is opened the journal is replayed (the last line
+// for a given issue key wins) and then compacted.  The compacted file starts
+// with a header line recording the GitHub repository of the project.
+
+package ledger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"lib.virginia.edu/agita/log"
+	"lib.virginia.edu/agita/util"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// Ledger file name extension.
+const LEDGER_EXT = ".jsonl"
+
+// File name extension for the file holding the time of the last run.
+const LAST_RUN_EXT = ".last"
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// Path (relative to project root unless absolute) of the directory holding
+// ledger files.
+var LEDGER_DIR = "tmp/ledger"
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// The transfer record for a Jira project.
+type Ledger struct {
+    Project string
+    Repo    string
+    entries map[string]*Entry
+    file    *os.File
+    mutex   sync.Mutex
+}
+
+// ============================================================================
+// Internal types
+// ============================================================================
+
+// The first line of a ledger file.
+type header struct {
+    Project string  `json:"project"`
+    Repo    string  `json:"repo"`
+}
+
+// ============================================================================
+// Internal variables
+// ============================================================================
+
+// Ledgers that have been opened during this run.
+var ledgers      = map[string]*Ledger{}
+var ledgersMutex sync.Mutex
+
+// Ledgers that have been loaded for reading only during this run.
+var snapshots = map[string]*Ledger{}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Get the ledger for the indicated Jira project, loading it from its file if
+// necessary.
+//  NOTE: never returns nil
+func Open(project, repo string) *Ledger {
+    ledgersMutex.Lock()
+    defer ledgersMutex.Unlock()
+    if lg := ledgers[project]; lg != nil {
+        return lg
+    }
+    lg := &Ledger{Project: project, Repo: repo, entries: map[string]*Entry{}}
+    if err := lg.load(); err != nil {
+        log.Error("ledger %q: %v", project, err)
+    }
+    if err := lg.compact(); err != nil {
+        log.Error("ledger %q: %v", project, err)
+    }
+    ledgers[project] = lg
+    delete(snapshots, project)
+    return lg
+}
+
+// Get the ledger for the indicated Jira project for reading only.  Unless it
+// has been opened during this run, the ledger file is replayed without being
+// compacted or left open, so the ledger cannot be updated.
+//  NOTE: returns nil if there is no ledger file.
+func Lookup(project string) *Ledger {
+    ledgersMutex.Lock()
+    defer ledgersMutex.Unlock()
+    if lg := ledgers[project]; lg != nil {
+        return lg
+    } else if lg := snapshots[project]; lg != nil {
+        return lg
+    } else if !Exists(project) {
+        return nil
+    }
+    lg := &Ledger{Project: project, Repo: RecordedRepo(project), entries: map[string]*Entry{}}
+    if err := lg.load(); err != nil {
+        log.Error("ledger %q: %v", project, err)
+    }
+    snapshots[project] = lg
+    return lg
+}
+
+// Indicate whether a ledger file exists for the indicated Jira project.
+func Exists(project string) bool {
+    _, err := os.Stat(ledgerPath(project))
+    return err == nil
+}
+
+// The GitHub repository recorded in the ledger file for the indicated Jira
+// project.
+//  NOTE: returns blank if there is no ledger file or it has no header.
+func RecordedRepo(project string) string {
+    src, err := os.Open(ledgerPath(project))
+    if err != nil {
+        return ""
+    }
+    defer src.Close()
+    scanner := bufio.NewScanner(src)
+    scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+    hdr := header{}
+    if scanner.Scan() && (json.Unmarshal(scanner.Bytes(), &hdr) == nil) {
+        return hdr.Repo
+    }
+    return ""
+}
+
+// All ledgers that have been opened during this run, ordered by project.
+func Opened() []*Ledger {
+    ledgersMutex.Lock()
+    defer ledgersMutex.Unlock()
+    res := []*Ledger{}
+    for _, project := range slices.Sorted(maps.Keys(ledgers)) {
+        res = append(res, ledgers[project])
+    }
+    return res
+}
+
+// Close all ledgers that were opened or looked up during this run.
+func CloseAll() {
+    ledgersMutex.Lock()
+    defer ledgersMutex.Unlock()
+    for project, lg := range ledgers {
+        lg.close()
+        delete(ledgers, project)
+    }
+    clear(snapshots)
+}
+
+// Discard the ledger for the indicated Jira project.
+func Remove(project string) bool {
+    ledgersMutex.Lock()
+    defer ledgersMutex.Unlock()
+    if lg := ledgers[project]; lg != nil {
+        lg.close()
+        delete(ledgers, project)
+    }
+    delete(snapshots, project)
+    os.Remove(lastRunPath(project))
+    err := os.Remove(ledgerPath(project))
+    if errors.Is(err, fs.ErrNotExist) {
+        return false
+    }
+    return log.ErrorValue(err) == nil
+}
+
+// Generate a content hash from the JSON representation of the given values.
+func Hash(values ...any) string {
+    sum := sha256.New()
+    enc := json.NewEncoder(sum)
+    for _, value := range values {
+        if log.ErrorValue(enc.Encode(value)) != nil {
+            return ""
+        }
+    }
+    return hex.EncodeToString(sum.Sum(nil))
+}
+
+// ============================================================================
+// Exported members
+// ============================================================================
+
+// Return a copy of the entry for the given Jira issue key or nil if the issue
+// has no entry.
+//  NOTE: the members below may be invoked on a nil Ledger.
+func (l *Ledger) Get(key string) *Entry {
+    if l == nil { return nil }
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    return l.entries[key].clone()
+}
+
+// Return copies of all entries, ordered by Jira issue key.
+func (l *Ledger) Entries() []*Entry {
+    if l == nil { return nil }
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    keys := util.MapKeys(l.entries)
+    slices.SortFunc(keys, CompareKeys)
+    res := make([]*Entry, 0, len(keys))
+    for _, key := range keys {
+        res = append(res, l.entries[key].clone())
+    }
+    return res
+}
+
+// Modify the entry for the given Jira issue key (creating it if necessary)
+// and record the change in the ledger file.
+func (l *Ledger) Update(key string, change func(*Entry)) *Entry {
+    if l == nil { return nil }
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    entry := l.entries[key]
+    if entry == nil {
+        entry = &Entry{Key: key}
+        l.entries[key] = entry
+    }
+    change(entry)
+    entry.Updated = time.Now()
+    if err := l.append(entry); err != nil {
+        log.Error("ledger %q: %v", l.Project, err)
+    }
+    return entry.clone()
+}
+
+// The start time of the last run which brought the GitHub repository up to date
+// with the Jira project, or the zero time if there has been no such run.
+func (l *Ledger) LastRun() time.Time {
+    if l == nil { return time.Time{} }
+    data, err := os.ReadFile(lastRunPath(l.Project))
+    if errors.Is(err, fs.ErrNotExist) {
+        return time.Time{}
+    } else if log.ErrorValue(err) != nil {
+        return time.Time{}
+    }
+    res, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+    log.ErrorValue(err)
+    return res
+}
+
+// Record the start time of a run which brought the GitHub repository up to date
+// with the Jira project.
+func (l *Ledger) SetLastRun(start time.Time) {
+    if l == nil { return }
+    data := start.Format(time.RFC3339) + "\n"
+    log.ErrorValue(os.WriteFile(lastRunPath(l.Project), []byte(data), 0644))
+}
+
+// Record a GitHub comment ID for the given Jira comment of the issue.
+func (l *Ledger) AddComment(key, jiraID string, githubID int64) {
+    l.Update(key, func(e *Entry) {
+        if e.Comments == nil {
+            e.Comments = map[string]int64{}
+        }
+        e.Comments[jiraID] = githubID
+    })
+}
+
+// Record the Jira keys referenced by the issue which could not yet be resolved
+// to GitHub issues.
+func (l *Ledger) SetUnresolved(key string, keys []string) {
+    l.Update(key, func(e *Entry) {
+        e.Unresolved = slices.Clone(keys)
+    })
+}
+
+// Record GitHub relationships to be created for the issue, ignoring any which
+// have already been recorded.
+func (l *Ledger) AddLinks(key string, links []Link) {
+    entry := l.Get(key)
+    added := slices.DeleteFunc(slices.Clone(links), func(link Link) bool {
+        return (entry != nil) && slices.ContainsFunc(entry.Links, link.same)
+    })
+    if len(added) > 0 {
+        l.Update(key, func(e *Entry) {
+            e.Links = append(e.Links, added...)
+        })
+    }
+}
+
+// Record that a GitHub relationship for the issue has been created.
+func (l *Ledger) LinkCreated(key string, link Link) {
+    l.Update(key, func(e *Entry) {
+        for idx := range e.Links {
+            if e.Links[idx].same(link) {
+                e.Links[idx].Created = true
+            }
+        }
+    })
+}
+
+// Record GitHub issue properties to be applied to the issue.  A property whose
+// value has changed must be applied again; others are left as they are.
+func (l *Ledger) SetProperties(key string, props []Property) {
+    entry   := l.Get(key)
+    changed := slices.DeleteFunc(slices.Clone(props), func(prop Property) bool {
+        return (entry != nil) && slices.ContainsFunc(entry.Properties, prop.same)
+    })
+    if len(changed) > 0 {
+        l.Update(key, func(e *Entry) {
+            for _, prop := range changed {
+                prop.Applied = false
+                named := func(p Property) bool { return p.Name == prop.Name }
+                if idx := slices.IndexFunc(e.Properties, named); idx < 0 {
+                    e.Properties = append(e.Properties, prop)
+                } else {
+                    e.Properties[idx] = prop
+                }
+            }
+        })
+    }
+}
+
+// Record that a GitHub issue property has been applied.
+func (l *Ledger) PropertyApplied(key string, prop Property) {
+    l.Update(key, func(e *Entry) {
+        for idx := range e.Properties {
+            if e.Properties[idx].same(prop) {
+                e.Properties[idx].Applied = true
+            }
+        }
+    })
+}
+
+// Record an attachment file as having been stored for the issue.
+func (l *Ledger) AddAttachment(key, file string) {
+    l.Update(key, func(e *Entry) {
+        if !slices.Contains(e.Attachments, file) {
+            e.Attachments = append(e.Attachments, file)
+        }
+    })
+}
+
+// ============================================================================
+// Internal members
+// ============================================================================
+
+// Replay the ledger file journal.
+func (l *Ledger) load() error {
+    src, err := os.Open(ledgerPath(l.Project))
+    if errors.Is(err, fs.ErrNotExist) {
+        return nil
+    } else if err != nil {
+        return err
+    }
+    defer src.Close()
+    scanner := bufio.NewScanner(src)
+    scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+    for line := 1; scanner.Scan(); line++ {
+        entry := &Entry{}
+        if line == 1 {
+            // Skip the header line if there is one.
+            hdr := header{}
+            if (json.Unmarshal(scanner.Bytes(), &hdr) == nil) && (hdr.Project != "") {
+                continue
+            }
+        }
+        if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+            // A truncated last line is expected after an interruption.
+            log.Warn("ledger %q line %d ignored: %v", l.Project, line, err)
+        } else if entry.Key != "" {
+            l.entries[entry.Key] = entry
+        }
+    }
+    return scanner.Err()
+}
+
+// Rewrite the ledger file with only the current entries then leave it open
+// for further updates.
+func (l *Ledger) compact() error {
+    path := ledgerPath(l.Project)
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        return err
+    }
+    temp := path + ".tmp"
+    dst, err := os.Create(temp)
+    if err != nil {
+        return err
+    }
+    enc  := json.NewEncoder(dst)
+    keys := util.MapKeys(l.entries)
+    slices.SortFunc(keys, CompareKeys)
+    err   = enc.Encode(header{Project: l.Project, Repo: l.Repo})
+    for _, key := range keys {
+        if err == nil {
+            err = enc.Encode(l.entries[key])
+        }
+    }
+    if cerr := dst.Close(); err == nil {
+        err = cerr
+    }
+    if err == nil {
+        err = os.Rename(temp, path)
+    }
+    if err == nil {
+        l.file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+    }
+    return err
+}
+
+// Add a line to the ledger file journal.
+func (l *Ledger) append(entry *Entry) error {
+    if l.file == nil {
+        return fmt.Errorf("ledger file not open")
+    }
+    line, err := json.Marshal(entry)
+    if err == nil {
+        _, err = l.file.Write(append(line, '\n'))
+    }
+    return err
+}
+
+// Close the ledger file.
+func (l *Ledger) close() {
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    if l.file != nil {
+        log.ErrorValue(l.file.Close())
+        l.file = nil
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The absolute path to the ledger file for the given Jira project.
+func ledgerPath(project string) string {
+    return filepath.Join(ledgerDir(), project + LEDGER_EXT)
+}
+
+// The absolute path to the last run file for the given Jira project.
+func lastRunPath(project string) string {
+    return filepath.Join(ledgerDir(), project + LAST_RUN_EXT)
+}
+
+// The absolute path to the directory holding ledger files.
+func ledgerDir() string {
+    if dir := LEDGER_DIR; filepath.IsAbs(dir) {
+        return dir
+    } else {
+        return filepath.Join(util.RootPath(), dir)
+    }
+}
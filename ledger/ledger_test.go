@@ -0,0 +1,236 @@
+// ledger/ledger_test.go
+
+package ledger
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestHash(t *testing.T) {
+    const fn = "Hash"
+
+	type testCase struct {
+		name  string
+		a     []any
+		b     []any
+		equal bool
+	}
+
+    Case := func(idx int, a, b []any, equal bool) testCase {
+        return testCase{test.CaseName(fn, idx), a, b, equal}
+    }
+
+	tests := []testCase{
+        Case(0, []any{"title", "body"},  []any{"title", "body"},   true),
+        Case(1, []any{"title", "body"},  []any{"title", "body "},  false),
+        Case(2, []any{"titlebody"},      []any{"title", "body"},   false),
+        Case(3, []any{[]string{"a"}},    []any{[]string{"a"}},     true),
+        Case(4, []any{},                 []any{},                  true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            a, b := Hash(tt.a...), Hash(tt.b...)
+            if a == "" {
+                t.Errorf("%s(%v) = blank", fn, tt.a)
+            } else if got := (a == b); got != tt.equal {
+                t.Errorf("%s(%v) == %s(%v) is %v, want %v", fn, tt.a, fn, tt.b, got, tt.equal)
+            }
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+    const fn = "Open"
+
+    const (
+        project = "TESTOPEN"
+        repo    = "test-open-repo"
+    )
+    LEDGER_DIR = t.TempDir()
+    defer CloseAll()
+
+    if Exists(project) {
+        t.Fatalf("%s: ledger exists before being opened", fn)
+    }
+    lg := Open(project, repo)
+    lg.Update(project + "-10", func(e *Entry) { e.Status = StatusFailed })
+    lg.Update(project + "-9",  func(e *Entry) { e.Status, e.ImportID = StatusPending, 7 })
+    lg.Update(project + "-9",  func(e *Entry) { e.Status, e.Issue = StatusImported, 3 })
+    lg.AddComment(project + "-9", "10001", 555)
+    lg.AddAttachment(project + "-9", "image.png")
+    lg.AddAttachment(project + "-9", "image.png")
+
+    if got := RecordedRepo(project); got != repo {
+        t.Errorf("RecordedRepo() = %q, want %q", got, repo)
+    }
+
+    // Reopen the ledger to replay its journal.
+    CloseAll()
+    lg = Open(project, repo)
+    keys := []string{}
+    for _, entry := range lg.Entries() {
+        keys = append(keys, entry.Key)
+    }
+    if want := []string{project + "-9", project + "-10"}; !slices.Equal(keys, want) {
+        t.Errorf("%s().Entries() keys = %v, want %v", fn, keys, want)
+    }
+    entry := lg.Get(project + "-9")
+    if !entry.Done() || (entry.Issue != 3) || (entry.ImportID != 7) {
+        t.Errorf("%s().Get() = %+v, want imported as issue 3", fn, entry)
+    }
+    if got := entry.Comment("10001"); got != 555 {
+        t.Errorf("%s().Get().Comment() = %d, want 555", fn, got)
+    }
+    if got := entry.Attachments; !slices.Equal(got, []string{"image.png"}) {
+        t.Errorf("%s().Get().Attachments = %v, want [image.png]", fn, got)
+    }
+    if !lg.Get(project + "-10").Failed() {
+        t.Errorf("%s().Get(%q) not failed", fn, project + "-10")
+    }
+    if got := lg.Get(project + "-11"); got != nil {
+        t.Errorf("%s().Get(%q) = %+v, want nil", fn, project + "-11", got)
+    }
+
+    // A copy of an entry does not change the ledger.
+    entry.Attachments[0] = "changed.png"
+    if got := lg.Get(project + "-9").Attachments[0]; got != "image.png" {
+        t.Errorf("%s().Get() shares attachments with the ledger", fn)
+    }
+}
+
+func TestLookup(t *testing.T) {
+    const fn = "Lookup"
+
+    const (
+        project = "TESTLOOKUP"
+        repo    = "test-lookup-repo"
+    )
+    LEDGER_DIR = t.TempDir()
+    defer CloseAll()
+
+    if got := Lookup(project); got != nil {
+        t.Errorf("%s() = %+v, want nil without a ledger file", fn, got)
+    }
+    Open(project, repo).Update(project + "-1", func(e *Entry) {
+        e.Status, e.Issue = StatusImported, 1
+    })
+    CloseAll()
+
+    before, err := os.ReadFile(ledgerPath(project))
+    if err != nil {
+        t.Fatalf("%s: %v", fn, err)
+    }
+    lg := Lookup(project)
+    if lg == nil {
+        t.Fatalf("%s() = nil, want ledger", fn)
+    }
+    if lg.Repo != repo {
+        t.Errorf("%s().Repo = %q, want %q", fn, lg.Repo, repo)
+    }
+    if !lg.Get(project + "-1").Done() {
+        t.Errorf("%s().Get() not done", fn)
+    }
+
+    // A snapshot cannot be updated and does not rewrite the ledger file.
+    lg.Update(project + "-2", func(e *Entry) { e.Status = StatusFailed })
+    after, err := os.ReadFile(ledgerPath(project))
+    if err != nil {
+        t.Fatalf("%s: %v", fn, err)
+    }
+    if string(after) != string(before) {
+        t.Errorf("%s() changed the ledger file", fn)
+    }
+}
+
+func TestRemove(t *testing.T) {
+    const fn = "Remove"
+
+    const project = "TESTREMOVE"
+    LEDGER_DIR = t.TempDir()
+    defer CloseAll()
+
+    Open(project, "test-remove-repo")
+    if !Exists(project) {
+        t.Fatalf("%s: ledger file not created", fn)
+    }
+    if !Remove(project) {
+        t.Errorf("%s() = false, want true", fn)
+    }
+    if Exists(project) {
+        t.Errorf("%s: ledger file not removed", fn)
+    }
+    if Remove(project) {
+        t.Errorf("%s() of a missing ledger = true, want false", fn)
+    }
+}
+
+// ============================================================================
+// Tests - Exported members
+// ============================================================================
+
+func TestAddLinks(t *testing.T) {
+    const fn = "AddLinks"
+
+    const (
+        project = "TESTLINKS"
+        key     = project + "-1"
+    )
+    LEDGER_DIR = t.TempDir()
+    defer CloseAll()
+
+    lg      := Open(project, "test-links-repo")
+    blocked := Link{Kind: LinkBlockedBy, Key: project + "-2"}
+    parent  := Link{Kind: LinkSubIssueOf, Key: project + "-3"}
+    lg.AddLinks(key, []Link{blocked})
+    lg.LinkCreated(key, blocked)
+    lg.AddLinks(key, []Link{blocked, parent})
+
+    links := lg.Get(key).Links
+    if len(links) != 2 {
+        t.Fatalf("%s: links = %v, want 2", fn, links)
+    }
+    if !links[0].Created {
+        t.Errorf("%s: created link %v was replaced", fn, links[0])
+    }
+    if got := lg.Get(key).PendingLinks(); (len(got) != 1) || !got[0].same(parent) {
+        t.Errorf("%s: pending links = %v, want [%v]", fn, got, parent)
+    }
+}
+
+func TestSetProperties(t *testing.T) {
+    const fn = "SetProperties"
+
+    const (
+        project = "TESTPROPS"
+        key     = project + "-1"
+    )
+    LEDGER_DIR = t.TempDir()
+    defer CloseAll()
+
+    lg     := Open(project, "test-props-repo")
+    kind   := Property{Name: PropertyIssueType,   Value: "Bug"}
+    reason := Property{Name: PropertyStateReason, Value: "completed"}
+    lg.SetProperties(key, []Property{kind, reason})
+    lg.PropertyApplied(key, kind)
+    lg.PropertyApplied(key, reason)
+
+    // An unchanged property remains applied; a changed one must be reapplied.
+    changed := Property{Name: PropertyStateReason, Value: "not_planned"}
+    lg.SetProperties(key, []Property{kind, changed})
+
+    props := lg.Get(key).Properties
+    if len(props) != 2 {
+        t.Fatalf("%s: properties = %v, want 2", fn, props)
+    }
+    if got := lg.Get(key).PendingProperties(); (len(got) != 1) || !got[0].same(changed) {
+        t.Errorf("%s: pending properties = %v, want [%v]", fn, got, changed)
+    }
+}
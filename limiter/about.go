@@ -0,0 +1,4 @@
+// limiter/about.go
+
+// Rate limiting for requests to the Jira and GitHub APIs.
+package limiter
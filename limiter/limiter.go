@@ -0,0 +1,246 @@
+// limiter/limiter.go
+//
+// Request budgets for a remote API.
+//
+// GitHub imposes a primary rate limit on requests of any kind (5000 per hour
+// for REST requests, with separate budgets for search requests and for GraphQL
+// points) which it reports in the headers of every response.  A GraphQL query
+// costs a number of points depending on its complexity, so each is charged
+// the cost of the last GraphQL query as reported by the change in points used.
+// It also imposes secondary rate limits, the most stringent of which is no more
+// than 80 content-creating requests per minute, which it does not report until
+// they have been exceeded.
+//
+// A Limiter tracks the budgets reported by the server for each resource and
+// the content-creating requests made within the last minute, and delays any
+// request which would exceed them.  If the server reports that a limit was
+// exceeded anyway, requests are paused until the time given by `Retry-After`
+// or the budget reset time.
+//
+// @see https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api
+// @see https://docs.github.com/en/graphql/overview/rate-limits-and-node-limits-for-the-graphql-api
+
+package limiter
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"lib.virginia.edu/agita/log"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// GitHub accepts no more than 80 content-creating requests per minute.
+const GITHUB_WRITES_PER_MINUTE = 80
+
+// The number of requests in a budget which are held back.
+const RESERVE = 1
+
+// Time added to a server-provided reset time to allow for clock differences.
+const RESET_MARGIN = 5 * time.Second
+
+// Pause after a rate limit response which does not indicate when to retry.
+const SECONDARY_PAUSE = time.Minute
+
+// The budget for requests which do not name a resource.
+const CORE = "core"
+
+// The budget for GitHub GraphQL requests.
+const GRAPHQL = "graphql"
+
+// The budget for GitHub REST search requests.
+const SEARCH = "search"
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A request budget as reported by the server.
+type Budget struct {
+    Limit     int
+    Remaining int
+    Used      int
+    Reset     time.Time
+}
+
+// Rate limit accounting for a remote API.
+type Limiter struct {
+    Name            string
+    WritesPerMinute int     // If zero, content-creating requests are not limited.
+    budgets         map[string]*Budget
+    costs           map[string]int  // Cost of the last request by resource.
+    writes          []time.Time
+    paused          time.Time
+    mutex           sync.Mutex
+}
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// The limiter for all GitHub REST and GraphQL requests.
+var Github = New("GITHUB", GITHUB_WRITES_PER_MINUTE)
+
+// The limiter for all Jira requests.
+var Jira = New("JIRA", 0)
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Create a new Limiter instance.
+//  NOTE: never returns nil
+func New(name string, writesPerMinute int) *Limiter {
+    return &Limiter{
+        Name:            name,
+        WritesPerMinute: writesPerMinute,
+        budgets:         map[string]*Budget{},
+        costs:           map[string]int{},
+    }
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// The last reported budget for the given resource.
+//  NOTE: Limit is zero if the server has not reported on the resource.
+func (l *Limiter) Budget(resource string) Budget {
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    if budget := l.budgets[resource]; budget != nil {
+        return *budget
+    }
+    return Budget{}
+}
+
+// Block until the request can be made without exceeding a rate limit.
+func (l *Limiter) Wait(req *http.Request) {
+    resource := resourceFor(req)
+    write    := isWrite(req)
+    for {
+        l.mutex.Lock()
+        now   := time.Now()
+        pause := l.delay(resource, write, now)
+        if pause <= 0 {
+            if budget := l.budgets[resource]; budget != nil {
+                budget.Remaining -= max(1, l.costs[resource])
+            }
+            if write && (l.WritesPerMinute > 0) {
+                l.writes = append(l.writes, now)
+            }
+            l.mutex.Unlock()
+            return
+        }
+        l.mutex.Unlock()
+        log.Warn("%s RATE LIMIT PAUSE: %v (%s)", l.Name, pause.Round(time.Second), l.State())
+        time.Sleep(pause)
+    }
+}
+
+// Record the budget reported in the response headers.
+//  NOTE: returns true if the request was rejected by a rate limit (including
+//  a GraphQL response reporting a RATE_LIMITED error).
+func (l *Limiter) Update(rsp *http.Response) bool {
+    if rsp == nil { return false }
+    resource, budget := budgetFrom(rsp.Header)
+    limited := (rsp.StatusCode == http.StatusTooManyRequests)
+    switch {
+        case limited:
+            // Already known to be limited.
+        case rsp.StatusCode == http.StatusForbidden:
+            limited = (budget != nil) && (budget.Remaining == 0)
+            limited = limited || (rsp.Header.Get("Retry-After") != "")
+            limited = limited || secondaryLimited(rsp)
+        case (rsp.Request != nil) && (resourceFor(rsp.Request) == GRAPHQL):
+            limited = graphqlLimited(rsp)
+    }
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    if budget != nil {
+        if prev := l.budgets[resource]; (prev != nil) && prev.Reset.Equal(budget.Reset) && (budget.Used > prev.Used) {
+            l.costs[resource] = budget.Used - prev.Used
+        }
+        l.budgets[resource] = budget
+    }
+    if limited {
+        until := retryAfter(rsp.Header)
+        if until.IsZero() && (budget != nil) && (budget.Remaining == 0) {
+            until = budget.Reset.Add(RESET_MARGIN)
+        }
+        if until.IsZero() {
+            until = time.Now().Add(SECONDARY_PAUSE)
+        }
+        if until.After(l.paused) {
+            l.paused = until
+        }
+    }
+    return limited
+}
+
+// A description of the current rate limit state for logging.
+func (l *Limiter) State() string {
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    now := time.Now()
+    res := []string{}
+    for _, resource := range slices.Sorted(maps.Keys(l.budgets)) {
+        b := l.budgets[resource]
+        s := fmt.Sprintf("%s %d/%d", resource, b.Remaining, b.Limit)
+        if !b.Reset.IsZero() {
+            s += fmt.Sprintf(" reset %s", b.Reset.Format(time.TimeOnly))
+        }
+        res = append(res, s)
+    }
+    if l.WritesPerMinute > 0 {
+        l.pruneWrites(now)
+        res = append(res, fmt.Sprintf("writes %d/%d per minute", len(l.writes), l.WritesPerMinute))
+    }
+    if l.paused.After(now) {
+        res = append(res, fmt.Sprintf("paused until %s", l.paused.Format(time.TimeOnly)))
+    }
+    if len(res) == 0 {
+        return "no requests"
+    }
+    return strings.Join(res, "; ")
+}
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// The time to wait before a request for the given resource can be made.
+//  NOTE: must be called with the mutex held.
+func (l *Limiter) delay(resource string, write bool, now time.Time) time.Duration {
+    if l.paused.After(now) {
+        return l.paused.Sub(now)
+    }
+    if b := l.budgets[resource]; (b != nil) && (b.Remaining <= RESERVE) && b.Reset.After(now) {
+        return b.Reset.Add(RESET_MARGIN).Sub(now)
+    }
+    if write && (l.WritesPerMinute > 0) {
+        if l.pruneWrites(now); len(l.writes) >= l.WritesPerMinute {
+            return l.writes[0].Add(time.Minute).Sub(now)
+        }
+    }
+    return 0
+}
+
+// Discard the times of content-creating requests made over a minute ago.
+//  NOTE: must be called with the mutex held.
+func (l *Limiter) pruneWrites(now time.Time) {
+    start := now.Add(-time.Minute)
+    idx   := 0
+    for (idx < len(l.writes)) && !l.writes[idx].After(start) {
+        idx++
+    }
+    l.writes = l.writes[idx:]
+}
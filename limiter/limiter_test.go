@@ -0,0 +1,143 @@
+// limiter/limiter_test.go
+
+package limiter
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Exported methods
+// ============================================================================
+
+func TestUpdate(t *testing.T) {
+    const fn = "Update"
+
+	type testCase struct {
+		name    string
+		status  int
+		header  map[string]string
+		body    string
+		url     string
+		limited bool
+	}
+
+    Case := func(idx int, status int, header map[string]string, body string, limited bool) testCase {
+        return testCase{test.CaseName(fn, idx), status, header, body, "https://api.github.com/repos/x/y", limited}
+    }
+
+    exhausted := map[string]string{
+        "X-RateLimit-Limit":     "5000",
+        "X-RateLimit-Remaining": "0",
+        "X-RateLimit-Reset":     strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+    }
+    remaining := map[string]string{
+        "X-RateLimit-Limit":     "5000",
+        "X-RateLimit-Remaining": "100",
+    }
+    retry := map[string]string{"Retry-After": "30"}
+    gql   := Case(6, http.StatusOK, nil, `{"errors":[{"type":"RATE_LIMITED"}]}`, true)
+    gql.url = "https://api.github.com/graphql"
+
+	tests := []testCase{
+        Case(0, http.StatusOK,              remaining, "",                                false),
+        Case(1, http.StatusForbidden,       exhausted, "",                                true),
+        Case(2, http.StatusForbidden,       remaining, "",                                false),
+        Case(3, http.StatusForbidden,       retry,     "",                                true),
+        Case(4, http.StatusForbidden,       nil,       "You have exceeded a secondary rate limit", true),
+        Case(5, http.StatusTooManyRequests, nil,       "",                                true),
+        gql,
+        Case(7, http.StatusOK,              nil,       `{"errors":[{"type":"RATE_LIMITED"}]}`, false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+            rsp    := &http.Response{
+                StatusCode: tt.status,
+                Header:     http.Header{},
+                Body:       io.NopCloser(strings.NewReader(tt.body)),
+                Request:    req,
+            }
+            for k, v := range tt.header {
+                rsp.Header.Set(k, v)
+            }
+            lim := New("TEST", 0)
+            if got := lim.Update(rsp); got != tt.limited {
+                t.Errorf("%s() = %v, want %v", fn, got, tt.limited)
+            }
+            now := time.Now()
+            if got := lim.delay(CORE, false, now) > 0; got != tt.limited {
+                t.Errorf("%s() paused = %v, want %v", fn, got, tt.limited)
+            }
+		})
+	}
+}
+
+func TestUpdateCost(t *testing.T) {
+    const fn = "Update"
+
+    reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+    lim   := New("TEST", 0)
+    req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", nil)
+    update := func(used int) {
+        rsp := &http.Response{
+            StatusCode: http.StatusOK,
+            Header:     http.Header{},
+            Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+            Request:    req,
+        }
+        rsp.Header.Set("X-RateLimit-Limit",     "5000")
+        rsp.Header.Set("X-RateLimit-Remaining", strconv.Itoa(5000 - used))
+        rsp.Header.Set("X-RateLimit-Used",      strconv.Itoa(used))
+        rsp.Header.Set("X-RateLimit-Reset",     reset)
+        rsp.Header.Set("X-RateLimit-Resource",  GRAPHQL)
+        lim.Update(rsp)
+    }
+
+    // The cost of a query is the change in points used.
+    update(10)
+    update(35)
+    if got := lim.costs[GRAPHQL]; got != 25 {
+        t.Errorf("%s() cost = %d, want 25", fn, got)
+    }
+
+    // Each request is charged the cost of the last one.
+    lim.Wait(req)
+    if got := lim.Budget(GRAPHQL).Remaining; got != (5000 - 35 - 25) {
+        t.Errorf("%s() remaining after Wait = %d, want %d", fn, got, 5000 - 35 - 25)
+    }
+
+    // A request without a known cost is charged one point.
+    lim.Wait(req.Clone(req.Context()))
+    rest, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/x/y", nil)
+    lim.budgets[CORE] = &Budget{Limit: 5000, Remaining: 100}
+    lim.Wait(rest)
+    if got := lim.Budget(CORE).Remaining; got != 99 {
+        t.Errorf("%s() core remaining after Wait = %d, want 99", fn, got)
+    }
+}
+
+func TestWrites(t *testing.T) {
+    const fn = "delay"
+
+    lim := New("TEST", 2)
+    now := time.Now()
+    lim.writes = []time.Time{now.Add(-2 * time.Minute), now.Add(-30 * time.Second)}
+    if got := lim.delay(CORE, true, now); got != 0 {
+        t.Errorf("%s() with one recent write = %v, want 0", fn, got)
+    }
+    lim.writes = append(lim.writes, now.Add(-10 * time.Second))
+    if got := lim.delay(CORE, true, now); got != 30*time.Second {
+        t.Errorf("%s() with two recent writes = %v, want 30s", fn, got)
+    }
+    if got := lim.delay(CORE, false, now); got != 0 {
+        t.Errorf("%s() for a read = %v, want 0", fn, got)
+    }
+}
@@ -0,0 +1,195 @@
+// limiter/transport.go
+//
+// An HTTP transport which applies a Limiter to every request.
+
+package limiter
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// Number of times a request rejected by a rate limit is retried.
+const MAX_RETRIES = 3
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// An http.RoundTripper which delays requests to stay within rate limits and
+// retries requests which were rejected by a rate limit.
+type Transport struct {
+    Limiter *Limiter
+    Base    http.RoundTripper   // If nil, http.DefaultTransport is used.
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// Get a transport which applies the limiter to requests made through `base`.
+//  NOTE: never returns nil
+func (l *Limiter) Transport(base http.RoundTripper) *Transport {
+    return &Transport{Limiter: l, Base: base}
+}
+
+// Get an HTTP client whose requests are subject to the limiter.
+//  NOTE: never returns nil
+func (l *Limiter) Client() *http.Client {
+    return &http.Client{Transport: l.Transport(nil)}
+}
+
+// Perform the request when the limiter allows, retrying if it is rejected
+// by a rate limit.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+    base := t.Base
+    if base == nil {
+        base = http.DefaultTransport
+    }
+    for attempt := 0; ; attempt++ {
+        t.Limiter.Wait(req)
+        rsp, err := base.RoundTrip(req)
+        if (err != nil) || (attempt >= MAX_RETRIES) || !t.Limiter.Update(rsp) {
+            return rsp, err
+        }
+        next := replayable(req)
+        if next == nil {
+            return rsp, err
+        }
+        io.Copy(io.Discard, rsp.Body)
+        rsp.Body.Close()
+        req = next
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The name of the budget which applies to the request.
+func resourceFor(req *http.Request) string {
+    switch path := req.URL.Path; {
+        case strings.HasSuffix(path, "/graphql"):
+            return GRAPHQL
+        case strings.Contains(path, "/search/"):
+            return SEARCH
+    }
+    return CORE
+}
+
+// Indicate whether the request is content-creating.
+//  NOTE: a GraphQL request is content-creating only if it is a mutation.
+func isWrite(req *http.Request) bool {
+    switch req.Method {
+        case http.MethodGet, http.MethodHead, http.MethodOptions:
+            return false
+    }
+    if resourceFor(req) != GRAPHQL {
+        return true
+    }
+    if req.GetBody == nil {
+        return false
+    }
+    body, err := req.GetBody()
+    if err != nil {
+        return false
+    }
+    defer body.Close()
+    data, _ := io.ReadAll(body)
+    return bytes.Contains(data, []byte(`"query":"mutation`))
+}
+
+// Get a copy of the request which can be sent again.
+//  NOTE: returns nil if the request body cannot be replayed.
+func replayable(req *http.Request) *http.Request {
+    next := req.Clone(req.Context())
+    if (req.Body != nil) && (req.Body != http.NoBody) {
+        if req.GetBody == nil {
+            return nil
+        }
+        body, err := req.GetBody()
+        if err != nil {
+            return nil
+        }
+        next.Body = body
+    }
+    return next
+}
+
+// Extract the budget from response headers.
+//  NOTE: returns nil if the response does not report a budget.
+func budgetFrom(header http.Header) (resource string, budget *Budget) {
+    limit, err1 := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+    remain, err2 := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+    if (err1 != nil) || (err2 != nil) {
+        return
+    }
+    used, _ := strconv.Atoi(header.Get("X-RateLimit-Used"))
+    budget = &Budget{
+        Limit:     limit,
+        Remaining: remain,
+        Used:      used,
+        Reset:     parseTime(header.Get("X-RateLimit-Reset")),
+    }
+    if resource = header.Get("X-RateLimit-Resource"); resource == "" {
+        resource = CORE
+    }
+    return
+}
+
+// The time given by a `Retry-After` header.
+//  NOTE: returns a zero time if there is no `Retry-After` header.
+func retryAfter(header http.Header) time.Time {
+    value := header.Get("Retry-After")
+    if secs, err := strconv.Atoi(value); err == nil {
+        return time.Now().Add(time.Duration(secs) * time.Second)
+    }
+    if at, err := http.ParseTime(value); err == nil {
+        return at
+    }
+    return time.Time{}
+}
+
+// Interpret a reset time given either as epoch seconds (GitHub) or as an
+// RFC3339 timestamp (Jira).
+func parseTime(value string) time.Time {
+    if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+        return time.Unix(secs, 0)
+    }
+    if at, err := time.Parse(time.RFC3339, value); err == nil {
+        return at
+    }
+    return time.Time{}
+}
+
+// Indicate whether a 403 response reports a secondary rate limit.
+//  NOTE: the response body is restored so that it can be read by the caller.
+func secondaryLimited(rsp *http.Response) bool {
+    data := peekBody(rsp)
+    return bytes.Contains(bytes.ToLower(data), []byte("secondary rate limit"))
+}
+
+// Indicate whether a GraphQL response reports a RATE_LIMITED error (which
+// GitHub returns with a 200 status).
+//  NOTE: the response body is restored so that it can be read by the caller.
+func graphqlLimited(rsp *http.Response) bool {
+    data := peekBody(rsp)
+    return bytes.Contains(data, []byte(`"RATE_LIMITED"`))
+}
+
+// Read the response body, leaving a copy in its place.
+func peekBody(rsp *http.Response) []byte {
+    if rsp.Body == nil { return nil }
+    data, _ := io.ReadAll(rsp.Body)
+    rsp.Body.Close()
+    rsp.Body = io.NopCloser(bytes.NewReader(data))
+    return data
+}
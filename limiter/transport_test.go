@@ -0,0 +1,218 @@
+// limiter/transport_test.go
+
+package limiter
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestResourceFor(t *testing.T) {
+    const fn = "resourceFor"
+
+	type testCase struct {
+		name string
+		url  string
+		want string
+	}
+
+    Case := func(idx int, url, want string) testCase {
+        return testCase{test.CaseName(fn, idx), url, want}
+    }
+
+	tests := []testCase{
+        Case(0, "https://api.github.com/graphql",                    GRAPHQL),
+        Case(1, "https://api.github.com/search/issues?q=repo:x/y",   SEARCH),
+        Case(2, "https://api.github.com/repos/x/y/issues",           CORE),
+        Case(3, "https://jira.example.com/rest/api/2/search",        CORE),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+            if got := resourceFor(req); got != tt.want {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.url, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestIsWrite(t *testing.T) {
+    const fn = "isWrite"
+
+	type testCase struct {
+		name   string
+		method string
+		url    string
+		body   string
+		want   bool
+	}
+
+    Case := func(idx int, method, url, body string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), method, url, body, want}
+    }
+
+    rest  := "https://api.github.com/repos/x/y/issues"
+    gql   := "https://api.github.com/graphql"
+    tests := []testCase{
+        Case(0, http.MethodGet,   rest, "",                              false),
+        Case(1, http.MethodPost,  rest, `{"title":"t"}`,                 true),
+        Case(2, http.MethodPatch, rest, `{"title":"t"}`,                 true),
+        Case(3, http.MethodPost,  gql,  `{"query":"query{viewer{id}}"}`, false),
+        Case(4, http.MethodPost,  gql,  `{"query":"mutation($input:X!){}"}`, true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            var body io.Reader
+            if tt.body != "" {
+                body = strings.NewReader(tt.body)
+            }
+            req, _ := http.NewRequest(tt.method, tt.url, body)
+            if got := isWrite(req); got != tt.want {
+                t.Errorf("%s(%s %q) = %v, want %v", fn, tt.method, tt.body, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestBudgetFrom(t *testing.T) {
+    const fn = "budgetFrom"
+
+	type testCase struct {
+		name     string
+		header   map[string]string
+		resource string
+		want     *Budget
+	}
+
+    Case := func(idx int, header map[string]string, resource string, want *Budget) testCase {
+        return testCase{test.CaseName(fn, idx), header, resource, want}
+    }
+
+    reset := time.Unix(1700000000, 0)
+    tests := []testCase{
+        Case(0, map[string]string{}, "", nil),
+        Case(1, map[string]string{
+            "X-RateLimit-Limit":     "5000",
+            "X-RateLimit-Remaining": "4990",
+            "X-RateLimit-Used":      "10",
+            "X-RateLimit-Reset":     "1700000000",
+        }, CORE, &Budget{Limit: 5000, Remaining: 4990, Used: 10, Reset: reset}),
+        Case(2, map[string]string{
+            "X-RateLimit-Limit":     "30",
+            "X-RateLimit-Remaining": "29",
+            "X-RateLimit-Resource":  SEARCH,
+        }, SEARCH, &Budget{Limit: 30, Remaining: 29}),
+        Case(3, map[string]string{
+            "X-RateLimit-Limit":     "5000",
+            "X-RateLimit-Remaining": "many",
+        }, "", nil),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            header := http.Header{}
+            for k, v := range tt.header {
+                header.Set(k, v)
+            }
+            resource, got := budgetFrom(header)
+            if resource != tt.resource {
+                t.Errorf("%s() resource = %q, want %q", fn, resource, tt.resource)
+            }
+            switch {
+                case (got == nil) != (tt.want == nil):
+                    t.Errorf("%s() = %+v, want %+v", fn, got, tt.want)
+                case got == nil:
+                    // Both nil.
+                case (got.Limit != tt.want.Limit) || (got.Remaining != tt.want.Remaining) ||
+                     (got.Used != tt.want.Used) || !got.Reset.Equal(tt.want.Reset):
+                    t.Errorf("%s() = %+v, want %+v", fn, *got, *tt.want)
+            }
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+    const fn = "parseTime"
+
+	type testCase struct {
+		name  string
+		value string
+		want  time.Time
+	}
+
+    Case := func(idx int, value string, want time.Time) testCase {
+        return testCase{test.CaseName(fn, idx), value, want}
+    }
+
+	tests := []testCase{
+        Case(0, "1700000000",           time.Unix(1700000000, 0)),
+        Case(1, "2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+        Case(2, "",                     time.Time{}),
+        Case(3, "tomorrow",             time.Time{}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := parseTime(tt.value); !got.Equal(tt.want) {
+                t.Errorf("%s(%q) = %v, want %v", fn, tt.value, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+    const fn = "retryAfter"
+
+    at := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+    header := http.Header{}
+    if got := retryAfter(header); !got.IsZero() {
+        t.Errorf("%s() without header = %v, want zero", fn, got)
+    }
+    header.Set("Retry-After", "60")
+    if got := time.Until(retryAfter(header)); (got < 59*time.Second) || (got > 60*time.Second) {
+        t.Errorf("%s(60) = now + %v, want now + 60s", fn, got)
+    }
+    header.Set("Retry-After", at.Format(http.TimeFormat))
+    if got := retryAfter(header); !got.Equal(at) {
+        t.Errorf("%s(%q) = %v, want %v", fn, header.Get("Retry-After"), got, at)
+    }
+}
+
+func TestGraphqlLimited(t *testing.T) {
+    const fn = "graphqlLimited"
+
+	type testCase struct {
+		name string
+		body string
+		want bool
+	}
+
+    Case := func(idx int, body string, want bool) testCase {
+        return testCase{test.CaseName(fn, idx), body, want}
+    }
+
+	tests := []testCase{
+        Case(0, `{"data":{"viewer":{"login":"x"}}}`,                          false),
+        Case(1, `{"errors":[{"type":"RATE_LIMITED","message":"limit"}]}`,    true),
+        Case(2, `{"errors":[{"type":"NOT_FOUND","message":"missing"}]}`,     false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            rsp := &http.Response{Body: io.NopCloser(strings.NewReader(tt.body))}
+            if got := graphqlLimited(rsp); got != tt.want {
+                t.Errorf("%s(%s) = %v, want %v", fn, tt.body, got, tt.want)
+            }
+            if body, _ := io.ReadAll(rsp.Body); string(body) != tt.body {
+                t.Errorf("%s() body not restored: %q", fn, body)
+            }
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// link.go
+//
+// GitHub relationships between transferred issues.
+//
+// Jira issue links are listed in a "Linked issues" section of the GitHub issue
+// body.  Where GitHub has a native equivalent, the relationship is also created
+// on GitHub once both issues have been transferred:
+//
+// * "is blocked by" becomes an issue dependency.
+// * "duplicates" (for a resolved issue) closes the issue as a duplicate.
+// * A Jira subtask becomes a sub-issue of the GitHub issue of its parent.
+//
+// Since a relationship is only created after both issues have been transferred
+// a parent always exists before its children are attached.  Parent and subtask
+// keys are also annotated in the issue body so that a relationship which could
+// not be created is still recorded.
+
+package main
+
+import (
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The GitHub relationships to be created for a Jira issue.
+func issueLinks(jiraIssue Jira.Issue) []ledger.Link {
+    res := []ledger.Link{}
+    for _, key := range convert.BlockedBy(jiraIssue) {
+        res = append(res, ledger.Link{Kind: ledger.LinkBlockedBy, Key: key})
+    }
+    if key := convert.DuplicateOf(jiraIssue); key != "" {
+        res = append(res, ledger.Link{Kind: ledger.LinkDuplicateOf, Key: key})
+    }
+    if key := jiraIssue.Parent(); key != "" {
+        res = append(res, ledger.Link{Kind: ledger.LinkSubIssueOf, Key: key})
+    }
+    return res
+}
+
+// Create pending GitHub relationships for the issues of every ledger used in
+// this run.
+func linkAll() {
+    if FakeTransfer { return }
+    for _, lg := range ledger.Opened() {
+        if count := createLinks(lg); count > 0 {
+            logSummary("%s ISSUE RELATIONSHIPS CREATED: %d", lg.Project, count)
+        }
+    }
+}
+
+// Create the pending relationships of the ledger's issues to issues which
+// have been transferred.
+func createLinks(lg *ledger.Ledger) (count int) {
+    for _, entry := range lg.Entries() {
+        if !entry.Done() {
+            continue
+        }
+        for _, link := range entry.PendingLinks() {
+            githubWriter.Do(func() {
+                if createLink(lg, entry, link) {
+                    lg.LinkCreated(entry.Key, link)
+                    count++
+                }
+            })
+        }
+    }
+    return
+}
+
+// Create a GitHub relationship if the related issue has been transferred.
+//  NOTE: returns false if the relationship was not created.
+func createLink(lg *ledger.Ledger, entry *ledger.Entry, link ledger.Link) bool {
+    repo, other := transferredIssue(link.Key)
+    if other == nil {
+        return false
+    }
+    client := Github.MainClient()
+    switch link.Kind {
+        case ledger.LinkBlockedBy:
+            return Github.AddBlockedBy(client, Github.ORG, lg.Repo, entry.Issue, repo, other.Issue)
+        case ledger.LinkDuplicateOf:
+            return Github.CloseAsDuplicate(client, Github.ORG, lg.Repo, entry.Issue, repo, other.Issue)
+        case ledger.LinkSubIssueOf:
+            return Github.AddSubIssue(client, Github.ORG, repo, other.Issue, lg.Repo, entry.Issue)
+        default:
+            logError("%s: UNKNOWN LINK KIND %q", entry.Key, link.Kind)
+            return false
+    }
+}
@@ -9,6 +9,10 @@ func main() {
         case ModeExport:    ExportAll(Args...)
         case ModeClear:     ClearAll(Args...)
         case ModeTrial:     TrialAll(Args...)
+        case ModePlan:      PlanAll(Args...)
+        case ModeSync:      SyncAll(Args...)
+        case ModeValidate:  ValidateAll(Args...)
+        case ModeDiscover:  DiscoverAll(Args...)
         default:            panic("main action undefined")
     }
 }
@@ -499,8 +499,10 @@ func jiraInlineInserted(line string) string {
 
 // Transform Jira superscript markdown.
 //  EXAMPLE: ^superscript^ => <sup>superscript</sup>
+//  NOTE: a caret starting an attachment link ("[^file]" or "[text|^file]")
+//  does not begin superscript.
 func jiraInlineSuperscript(line string) string {
-    return re.ReplaceAll(line, `\^(.+?)\^`, "<sup>$1</sup>")
+    return re.ReplaceAll(line, `(^|[^[|])\^([^^\]]+?)\^`, "$1<sup>$2</sup>")
 }
 
 // Transform Jira subscript markdown.
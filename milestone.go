@@ -0,0 +1,103 @@
+// milestone.go
+//
+// GitHub milestones for Jira versions and epics.
+//
+// Before the issues of a project are transferred, a milestone is created in
+// the target repository for each version of the Jira project and for each of
+// its epics; a milestone with the same title which already exists is updated
+// instead, so that changes like the release of a version are carried over by
+// later runs.  Each issue is then imported with the milestone of its first fix
+// version or, failing that, the milestone of its epic.
+//
+// Only milestones made by this program (whose descriptions end with the tag
+// given by convert.MilestoneTag) are updated; a milestone of the same title
+// which belongs to the repository itself is used as it is.
+
+package main
+
+import (
+	"sync"
+
+	"lib.virginia.edu/agita/convert"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Variables
+// ============================================================================
+
+// For each repository, the numbers of the milestones prepared for it by the
+// Jira version or epic from which they were made.
+//  @see convert.MilestoneSource()
+var milestones = map[string]map[string]int{}
+
+// Guards access to milestones.
+var milestonesMutex sync.Mutex
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Create or update the GitHub milestones for the versions and epics of the
+// Jira project.
+//  NOTE: returns the number of milestones created or updated.
+func prepareMilestones(project *Jira.Project, repo string) (count int) {
+    if FakeTransfer { return }
+    wanted := map[string]Github.Milestone{}
+    for _, version := range project.Versions() {
+        wanted[convert.VersionSource(version.Name)] = convert.VersionMilestone(version)
+    }
+    for _, epic := range project.Epics() {
+        wanted[convert.EpicSource(epic.Key())] = convert.EpicMilestone(epic)
+    }
+    if len(wanted) == 0 {
+        return
+    }
+    client  := Github.MainClient()
+    numbers := map[string]int{}
+    githubWriter.Do(func() {
+        existing := Github.GetMilestones(client, Github.ORG, repo)
+        for source, milestone := range wanted {
+            current, found := existing[milestone.Title]
+            switch {
+                case !found:
+                    milestone.Number = Github.CreateMilestone(client, Github.ORG, repo, milestone)
+                    if milestone.Number == 0 {
+                        logError("%s: MILESTONE %q NOT CREATED", project.Key(), milestone.Title)
+                        continue
+                    }
+                    count++
+                case !convert.MadeFrom(current, source):
+                    logWarning("%s: MILESTONE %q ALREADY IN %s - NOT UPDATED", project.Key(), milestone.Title, repo)
+                    milestone.Number = current.Number
+                case !current.Same(milestone):
+                    milestone.Number = current.Number
+                    if Github.EditMilestone(client, Github.ORG, repo, milestone) {
+                        count++
+                    }
+                default:
+                    milestone.Number = current.Number
+            }
+            numbers[source] = milestone.Number
+        }
+    })
+    milestonesMutex.Lock()
+    defer milestonesMutex.Unlock()
+    if milestones[repo] == nil {
+        milestones[repo] = map[string]int{}
+    }
+    for source, number := range numbers {
+        milestones[repo][source] = number
+    }
+    return
+}
+
+// The number of the GitHub milestone prepared for the Jira version or epic.
+//  NOTE: returns 0 if there is no such milestone.
+func milestoneNumber(repo, source string) int {
+    milestonesMutex.Lock()
+    defer milestonesMutex.Unlock()
+    return milestones[repo][source]
+}
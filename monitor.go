@@ -0,0 +1,380 @@
+// monitor.go
+//
+// Background tracking of asynchronous GitHub issue imports.
+//
+// GitHub queues each issue import request and processes it later, so an import
+// which is accepted may still fail.  The ImportMonitor polls the status of
+// each outstanding import, records the outcome in the project ledger, and
+// resubmits failed imports when the problem reported by GitHub can be
+// corrected.
+
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"lib.virginia.edu/agita/ledger"
+
+	"lib.virginia.edu/agita/Github"
+)
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+// If true, each issue import must be resolved before the next one is submitted
+// so that GitHub issue numbers follow Jira issue key order (even when failed
+// imports are corrected and resubmitted).
+const ORDERED_IMPORTS = true
+
+// Maximum number of unresolved imports when ORDERED_IMPORTS is false.
+const IMPORT_WINDOW = 20
+
+// Time between checks on the status of outstanding imports.
+const IMPORT_POLL_DELAY = 2 * time.Second
+
+// Number of times a corrected import will be resubmitted.
+const IMPORT_RETRIES = 2
+
+// Maximum time to wait for GitHub to resolve an import.
+const IMPORT_TIMEOUT = 10 * time.Minute
+
+// GitHub rejects issue and comment bodies longer than this.
+const MAX_BODY_LENGTH = 65536
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Tracks the outstanding issue imports for a single repository.
+type ImportMonitor struct {
+    repo    string
+    ledger  *ledger.Ledger
+    slots   chan struct{}
+    queue   chan *importRequest
+    done    chan struct{}
+    mutex   sync.Mutex
+    failed  []string
+}
+
+// An outstanding issue import.
+type importRequest struct {
+    key       string
+    id        int
+    issue     *Github.IssueImport
+    comments  []*Github.CommentImport
+    ids       []string  // Ledger key of each comment.
+    retries   int
+    submitted time.Time
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Create an import monitor for the repository and start it in the background.
+//  NOTE: never returns nil
+func NewImportMonitor(lg *ledger.Ledger, repo string) *ImportMonitor {
+    window := IMPORT_WINDOW
+    if ORDERED_IMPORTS {
+        window = 1
+    }
+    m := &ImportMonitor{
+        repo:   repo,
+        ledger: lg,
+        slots:  make(chan struct{}, window),
+        queue:  make(chan *importRequest, window),
+        done:   make(chan struct{}),
+    }
+    go m.run()
+    return m
+}
+
+// ============================================================================
+// Methods
+// ============================================================================
+
+// Submit an issue import request and track its progress.  This blocks while
+// the maximum number of imports are unresolved.
+//  NOTE: `ids` gives the ledger key of each comment.
+//  NOTE: returns false if GitHub did not accept the request.
+func (m *ImportMonitor) Submit(key string, issue *Github.IssueImport, comments []*Github.CommentImport, ids []string) bool {
+    m.Reserve()
+    return m.SubmitReserved(key, issue, comments, ids)
+}
+
+// Wait until another import may be submitted.  This must be followed by
+// SubmitReserved().
+func (m *ImportMonitor) Reserve() {
+    m.slots <- struct{}{}
+}
+
+// Submit an issue import request after Reserve() and track its progress.
+//  NOTE: `ids` gives the ledger key of each comment.
+//  NOTE: returns false if GitHub did not accept the request.
+func (m *ImportMonitor) SubmitReserved(key string, issue *Github.IssueImport, comments []*Github.CommentImport, ids []string) bool {
+    req := &importRequest{key: key, issue: issue, comments: comments, ids: ids}
+    if !m.submit(req) {
+        <-m.slots
+        return false
+    }
+    m.queue <- req
+    return true
+}
+
+// Track an import which was submitted during a previous run.  Since the
+// original request is not available, it cannot be resubmitted if it fails.
+func (m *ImportMonitor) Watch(key string, importID int) {
+    m.slots <- struct{}{}
+    m.queue <- &importRequest{key: key, id: importID, submitted: time.Now()}
+}
+
+// Wait for all outstanding imports to be resolved then return the keys of the
+// issues whose imports failed in Jira issue key order.
+func (m *ImportMonitor) Finish() []string {
+    close(m.queue)
+    <-m.done
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    slices.SortFunc(m.failed, ledger.CompareKeys)
+    return slices.Clone(m.failed)
+}
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// Send the import request to GitHub and record it in the ledger.
+func (m *ImportMonitor) submit(req *importRequest) bool {
+    client := Github.MainClient()
+    req.id  = Github.ImportIssue(client, Github.ORG, m.repo, req.issue, req.comments...)
+    req.submitted = time.Now()
+    if req.id == 0 {
+        logError("IMPORT REQUEST FAILED FOR ISSUE %q", req.key)
+        m.ledger.Update(req.key, func(e *ledger.Entry) {
+            e.Status, e.ImportID = ledger.StatusFailed, 0
+            e.Error = "import request not accepted"
+        })
+        m.addFailed(req.key)
+        return false
+    }
+    m.ledger.Update(req.key, func(e *ledger.Entry) {
+        e.Status, e.ImportID = ledger.StatusPending, req.id
+        e.Error = ""
+    })
+    return true
+}
+
+// Poll outstanding imports until the queue is closed and all imports have been
+// resolved.
+func (m *ImportMonitor) run() {
+    defer close(m.done)
+    active := []*importRequest{}
+    queue  := m.queue
+    for (queue != nil) || (len(active) > 0) {
+        // Wait for a new request if there is nothing else to do.
+        if len(active) == 0 {
+            if req, ok := <-queue; ok {
+                active = append(active, req)
+            } else {
+                queue = nil
+            }
+            continue
+        }
+
+        // Pick up any requests which have arrived since the last pass.
+    drain:
+        for queue != nil {
+            select {
+                case req, ok := <-queue:
+                    if ok {
+                        active = append(active, req)
+                    } else {
+                        queue = nil
+                    }
+                default:
+                    break drain
+            }
+        }
+
+        time.Sleep(IMPORT_POLL_DELAY)
+        active = slices.DeleteFunc(active, m.resolve)
+    }
+}
+
+// Check the status of an import; returns true if it has been resolved.
+func (m *ImportMonitor) resolve(req *importRequest) bool {
+    client := Github.MainClient()
+    status := Github.CheckIssueImport(client, Github.ORG, m.repo, req.id)
+    switch {
+        case status.Imported():
+            m.ledger.Update(req.key, func(e *ledger.Entry) {
+                e.Status, e.Issue, e.Error = ledger.StatusImported, status.Issue, ""
+            })
+            m.recordComments(req, status.Issue)
+
+        case status.Failed():
+            logError("IMPORT FAILED FOR ISSUE %q: %s", req.key, status.ErrorText())
+            m.ledger.Update(req.key, func(e *ledger.Entry) {
+                e.Status, e.Error = ledger.StatusFailed, status.ErrorText()
+            })
+            if m.retry(req, status.Errors) {
+                return false
+            }
+            m.addFailed(req.key)
+
+        case time.Since(req.submitted) > IMPORT_TIMEOUT:
+            // Leave as pending in the ledger to be checked on the next run.
+            logWarning("IMPORT STILL PENDING FOR ISSUE %q", req.key)
+
+        default:
+            return false
+    }
+    <-m.slots
+    return true
+}
+
+// Record the GitHub IDs of the comments created by an import so that
+// references to Jira comments can be linked to them and so that -sync can
+// update them.
+//  NOTE: an import submitted by a previous run has no comments to record.
+func (m *ImportMonitor) recordComments(req *importRequest, number int) {
+    if len(req.comments) == 0 {
+        return
+    }
+    client := Github.MainClient()
+    issue  := Github.GetIssue(client, Github.ORG, m.repo, number)
+    if issue == nil {
+        logWarning("ISSUE %q: COMMENTS NOT RECORDED: GITHUB ISSUE %d NOT FOUND", req.key, number)
+        return
+    }
+    found := matchComments(req.comments, req.ids, issue.Comments(), nil)
+    if len(found) > 0 {
+        m.ledger.Update(req.key, func(e *ledger.Entry) {
+            if e.Comments == nil {
+                e.Comments = map[string]int64{}
+            }
+            maps.Copy(e.Comments, found)
+        })
+    }
+}
+
+// Correct the import request based on the errors reported by GitHub and
+// resubmit it; returns false if the request could not be corrected.
+func (m *ImportMonitor) retry(req *importRequest, errs []Github.ImportError) bool {
+    if (req.issue == nil) || (req.retries >= IMPORT_RETRIES) {
+        return false
+    }
+    if !correctImport(req.issue, req.comments, errs) {
+        return false
+    }
+    req.retries++
+    logWarning("RESUBMITTING CORRECTED IMPORT FOR ISSUE %q", req.key)
+    ok := false
+    githubWriter.Do(func() {
+        ok = m.submit(req)
+    })
+    return ok
+}
+
+// Record an issue whose import could not be completed.
+func (m *ImportMonitor) addFailed(key string) {
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    if !slices.Contains(m.failed, key) {
+        m.failed = append(m.failed, key)
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Modify an import request to avoid the reported errors; returns false if no
+// change could be made.
+func correctImport(issue *Github.IssueImport, comments []*Github.CommentImport, errs []Github.ImportError) bool {
+    changed := false
+    for _, e := range errs {
+        inComment := strings.HasPrefix(e.Location, "/comment")
+        switch e.Field {
+            case "assignee":
+                if issue.Assignee != nil {
+                    issue.Assignee, changed = nil, true
+                }
+            case "milestone":
+                if issue.Milestone != nil {
+                    issue.Milestone, changed = nil, true
+                }
+            case "labels":
+                if len(issue.Labels) > 0 {
+                    issue.Labels, changed = nil, true
+                }
+            case "body":
+                if inComment {
+                    for _, comment := range comments {
+                        if body, ok := truncateBody(comment.Body); ok {
+                            comment.Body, changed = body, true
+                        }
+                    }
+                } else if body, ok := truncateBody(issue.Body); ok {
+                    issue.Body, changed = body, true
+                }
+        }
+    }
+    return changed
+}
+
+// Shorten body text that is too long for GitHub.
+func truncateBody(body string) (string, bool) {
+    const note = "\n\n_[TRUNCATED: original text exceeded GitHub limit]_"
+    if len(body) <= MAX_BODY_LENGTH {
+        return body, false
+    }
+    end := MAX_BODY_LENGTH - len(note)
+    for (end > 0) && !utf8.RuneStart(body[end]) {
+        end--
+    }
+    return body[:end] + note, true
+}
+
+// Pair converted comments with the GitHub comments which were created from
+// them, first by body and then by creation time.  Comments whose ledger key is
+// blank or already in `known` are skipped, as are the GitHub comments already
+// recorded there.
+//  NOTE: returns the GitHub comment ID by ledger key.
+func matchComments(comments []*Github.CommentImport, ids []string, found []*Github.Comment, known map[string]int64) map[string]int64 {
+    res  := map[string]int64{}
+    used := map[int64]bool{}
+    for _, id := range known {
+        used[id] = true
+    }
+    pair := func(same func(*Github.Comment, *Github.CommentImport) bool) {
+        for idx, comment := range comments {
+            key := ids[idx]
+            if (key == "") || (known[key] != 0) || (res[key] != 0) {
+                continue
+            }
+            for _, candidate := range found {
+                if id := candidate.ID(); !used[id] && same(candidate, comment) {
+                    res[key], used[id] = id, true
+                    break
+                }
+            }
+        }
+    }
+    pair(func(c *Github.Comment, i *Github.CommentImport) bool {
+        return c.Body() == i.Body
+    })
+    pair(func(c *Github.Comment, i *Github.CommentImport) bool {
+        // GitHub keeps creation times to the second.
+        if Github.NilTime(i.CreatedAt) { return false }
+        created := i.CreatedAt.Truncate(time.Second)
+        return c.CreatedAt().Truncate(time.Second).Equal(created)
+    })
+    return res
+}
@@ -0,0 +1,510 @@
+// pipeline.go
+//
+// Staged transfer of a Jira project's issues.
+//
+// Preparing an issue (fetching its comments, downloading its attachments, and
+// converting Jira content to GitHub content) involves only Jira requests and
+// local processing, so it is performed by a pool of workers.  Prepared issues
+// are then put back into Jira issue key order and handed one at a time to the
+// single GitHub writer stage, which is shared by all projects so that every
+// GitHub update is subject to the same rate limit accounting.
+
+package main
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+// Number of workers preparing issues for each project.
+const PIPELINE_WORKERS = 8
+
+// Maximum number of issues for each project which may be in preparation or
+// waiting to be written to GitHub.
+const PIPELINE_BUFFER = 32
+
+// Maximum number of projects transferred at the same time.
+const CONCURRENT_PROJECTS = 3
+
+// Maximum number of attachment files stored on GitHub in a single commit.
+const ATTACH_BATCH_FILES = 50
+
+// Maximum total size in bytes of the attachment files stored on GitHub in a
+// single commit.
+const ATTACH_BATCH_BYTES = 50 * 1024 * 1024
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// A Jira issue converted and ready to be written to GitHub.
+type PreparedIssue struct {
+    Seq         int
+    Key         string
+    Issue       *Github.IssueImport
+    Comments    []*Github.CommentImport
+    CommentIDs  []string            // Ledger key of each comment.
+    Attachments []*PreparedAttachment
+    Unresolved  []string            // Referenced Jira issues not yet transferred.
+    Hash        string              // Of the converted content; see issueHash().
+    Links       []ledger.Link
+    Milestone   string              // Jira version or epic of the GitHub milestone.
+    Labels      []Github.Label      // Labels synthesized from Jira fields.
+    Properties  []ledger.Property   // Applied after import.
+    Skip        bool                // Already transferred.
+    Watch       int                 // Import ID submitted by a previous run.
+    Report      string              // Field conversions; see logConversion().
+}
+
+// A downloaded Jira attachment which has not yet been stored on GitHub.
+type PreparedAttachment struct {
+    File        string
+    Path        string              // Staged copy of the content.
+    Size        int64
+    SHA256      string
+    Source      *AttachmentSource
+    Store       AttachmentStore     // Backend which will store the content.
+}
+
+// The single stage through which GitHub updates are made.
+type GithubWriter struct {
+    jobs chan func()
+}
+
+// Attachments of written issues which have not yet been stored on GitHub.
+type attachmentBatch struct {
+    repo    string
+    ledger  *ledger.Ledger
+    files   map[string]Github.ProjFile  // Content by stored file name.
+    stores  map[string]string           // Backend name by stored file name.
+    issues  map[string]string           // Jira issue key by attachment file name.
+    stored  map[string]string           // Stored file name by attachment file name.
+    staged  []string                    // Paths of staged attachments.
+    size    int64
+    first   string
+    last    string
+}
+
+// Transfer counts for a project.
+type transferStats struct {
+    first   string
+    last    string
+    total   int
+    skipped int
+}
+
+// ============================================================================
+// Variables
+// ============================================================================
+
+// The GitHub writer shared by all projects.
+var githubWriter = NewGithubWriter()
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Create a GitHub writer and start it in the background.
+//  NOTE: never returns nil
+func NewGithubWriter() *GithubWriter {
+    w := &GithubWriter{jobs: make(chan func())}
+    go func() {
+        for job := range w.jobs {
+            job()
+        }
+    }()
+    return w
+}
+
+// Convert a Jira issue and download any attachments which have not yet been
+// stored on GitHub.
+//  NOTE: if `lg` is nil then attachments are not downloaded.
+//  NOTE: never returns nil
+func PrepareIssue(jiraIssue Jira.Issue, lg *ledger.Ledger) *PreparedIssue {
+    key  := jiraIssue.Key()
+    prep := &PreparedIssue{Key: key}
+    var report *strings.Builder
+    if LOG_CONVERSIONS {
+        report = &strings.Builder{}
+    }
+    prep.Issue, prep.Comments, prep.CommentIDs, prep.Unresolved = convertIssue(jiraIssue, report)
+    if report != nil {
+        prep.Report = report.String()
+    }
+    prep.Hash       = issueHash(prep.Issue, prep.Comments)
+    prep.Links      = issueLinks(jiraIssue)
+    prep.Milestone  = convert.MilestoneSource(jiraIssue)
+    prep.Labels     = convert.SchemeLabels(jiraIssue)
+    prep.Properties = issueProperties(jiraIssue)
+    if FakeTransfer || (lg == nil) {
+        return prep
+    }
+    prep.Attachments = downloadAttachments(jiraIssue, lg)
+    if ATTACH_MIRROR_IMAGES {
+        prep.Attachments = append(prep.Attachments, mirrorImages(prep, lg)...)
+    }
+    return prep
+}
+
+// Batch attachments and submit the import request for a prepared issue.
+//  NOTE: must be preceded by monitor.Reserve()
+//  NOTE: if `batch` is nil then attachments are stored immediately.
+func WriteIssue(prep *PreparedIssue, repo string, monitor *ImportMonitor, batch *attachmentBatch) bool {
+    client := Github.MainClient()
+    lg     := monitor.ledger
+    key    := prep.Key
+    issue  := prep.Issue
+
+    // Save attachments which were not stored by a previous run, referring to
+    // the stored copy of any whose content is already in the repository.
+    if batch == nil {
+        batch = newAttachmentBatch(repo, lg)
+        defer batch.Flush()
+    }
+    if renames := batch.Add(prep, true); len(renames) > 0 {
+        issue.Body = renameAttachments(issue.Body, renames)
+        for _, comment := range prep.Comments {
+            comment.Body = renameAttachments(comment.Body, renames)
+        }
+    }
+
+    // An import with an unassignable assignee will fail.
+    if (issue.Assignee != nil) && !Github.IsAssignable(client, Github.ORG, repo, *issue.Assignee) {
+        logWarning("ISSUE %q: DROPPING UNASSIGNABLE ASSIGNEE %q", key, *issue.Assignee)
+        issue.Assignee = nil
+    }
+
+    // Synthesized labels must exist before the import is submitted.
+    issue.Labels = prepareLabels(repo, issue.Labels, prep.Labels)
+
+    // Milestones were created by prepareMilestones() for the project.
+    if number := milestoneNumber(repo, prep.Milestone); number != 0 {
+        issue.Milestone = &number
+    }
+
+    // Create the matching GitHub issue and comments.
+    ok := monitor.SubmitReserved(key, issue, prep.Comments, prep.CommentIDs)
+    lg.Update(key, func(e *ledger.Entry) {
+        e.Hash = prep.Hash
+    })
+    if len(prep.Unresolved) > 0 {
+        lg.SetUnresolved(key, prep.Unresolved)
+    }
+    if len(prep.Links) > 0 {
+        lg.AddLinks(key, prep.Links)
+    }
+    if len(prep.Properties) > 0 {
+        lg.SetProperties(key, prep.Properties)
+    }
+    return ok
+}
+
+// ============================================================================
+// Methods
+// ============================================================================
+
+// Run the function in the GitHub writer stage and wait for it to complete.
+func (w *GithubWriter) Do(job func()) {
+    done := make(chan struct{})
+    w.jobs <- func() {
+        defer close(done)
+        job()
+    }
+    <-done
+}
+
+// Add the attachments of a prepared issue to the batch, storing the batch on
+// GitHub first if the attachments would not fit.  Unless `dedupe` is false, an
+// attachment whose content is already stored is not stored again.
+//  NOTE: content which is only batched is stored again since the batch may
+//  fail to be stored after the issue which refers to it has been imported.
+//  NOTE: returns the URL of the stored copy by attachment URL for each
+//  attachment which refers to a copy stored elsewhere.
+//  NOTE: must be run in the GitHub writer stage.
+func (b *attachmentBatch) Add(prep *PreparedIssue, dedupe bool) map[string]string {
+    renames := map[string]string{}
+    if len(prep.Attachments) == 0 {
+        return renames
+    }
+    size := int64(0)
+    for _, attach := range prep.Attachments {
+        size += attach.Size
+    }
+    files := len(b.files) + len(prep.Attachments)
+    if (files > ATTACH_BATCH_FILES) || (b.size + size > ATTACH_BATCH_BYTES) {
+        b.Flush()
+    }
+    manifest := RepoManifest(b.repo, b.ledger.Project)
+    for _, attach := range prep.Attachments {
+        file, store := manifest.Add(attach, dedupe)
+        if store {
+            b.files[file.File]  = Github.ProjFile{Name: file.File, Path: attach.Path}
+            b.stores[file.File] = file.Store
+            b.size += attach.Size
+        } else if url := attach.Store.Url(attach.File); url != file.Url {
+            renames[url] = file.Url
+        }
+        b.issues[attach.File] = prep.Key
+        b.stored[attach.File] = file.File
+        b.staged = append(b.staged, attach.Path)
+    }
+    if b.first == "" { b.first = prep.Key }
+    b.last = prep.Key
+    return renames
+}
+
+// Store the batched attachments with their backends, verify the stored files,
+// and record them in the ledger.  The updated ATTACH_MANIFEST is stored last,
+// together with any files kept by the backend which keeps the manifest (see
+// ManifestStoreFor()).
+//  NOTE: must be run in the GitHub writer stage.
+func (b *attachmentBatch) Flush() {
+    if len(b.issues) == 0 {
+        return
+    }
+    issues := b.first
+    if b.last != b.first {
+        issues += " through " + b.last
+    }
+    message := "Stored attachments for " + issues
+    groups  := map[string][]Github.ProjFile{}
+    for _, name := range slices.Sorted(maps.Keys(b.files)) {
+        store := b.stores[name]
+        groups[store] = append(groups[store], b.files[name])
+    }
+
+    // Files kept elsewhere are stored first so that any which fail can be
+    // left out of the manifest.
+    manifest := RepoManifest(b.repo, b.ledger.Project)
+    keeper   := ManifestStoreFor(b.ledger.Project)
+    failed   := []string{}
+    for _, name := range slices.Sorted(maps.Keys(groups)) {
+        if name != keeper.Name() {
+            store := AttachmentStoreNamed(name)
+            failed = append(failed, b.store(store, issues, message, groups[name])...)
+        }
+    }
+    manifest.Remove(failed...)
+    files := append(groups[keeper.Name()], Github.ProjFile{Name: ATTACH_MANIFEST, Content: manifest.Json()})
+    stale := b.store(keeper, issues, message, files)
+    if slices.Contains(stale, ATTACH_MANIFEST) {
+        DiscardManifest(b.repo)
+    }
+    failed = append(failed, stale...)
+    manifest.Stored(slices.Collect(maps.Keys(b.files))...)
+    for file, key := range b.issues {
+        if !slices.Contains(failed, b.stored[file]) {
+            b.ledger.AddAttachment(key, file)
+        }
+    }
+    if !ATTACH_CACHE {
+        for _, path := range b.staged {
+            os.Remove(path)
+        }
+    }
+    b.files  = map[string]Github.ProjFile{}
+    b.stores = map[string]string{}
+    b.issues = map[string]string{}
+    b.stored = map[string]string{}
+    b.staged = []string{}
+    b.size   = 0
+    b.first  = ""
+    b.last   = ""
+}
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// Store batched files with a backend and verify them.
+//  NOTE: returns the names of the files which were not stored.
+func (b *attachmentBatch) store(store AttachmentStore, issues, message string, files []Github.ProjFile) []string {
+    var tooLarge *Github.FileTooLargeError
+    err := store.Store(b.repo, message, files)
+    switch {
+        case errors.As(err, &tooLarge):
+            logError("ATTACHMENTS FOR %s NOT STORED IN %s: %q IS %d BYTES (LIMIT %d)", issues, store.Name(), tooLarge.File, tooLarge.Size, tooLarge.Limit)
+        case err != nil:
+            logError("ATTACHMENTS FOR %s NOT STORED IN %s: %v", issues, store.Name(), err)
+        default:
+            failed := store.Verify(b.repo, files)
+            for _, file := range failed {
+                logError("%s: ATTACHMENT %q NOT VERIFIED IN %s", b.repo, file, store.Name())
+            }
+            return failed
+    }
+    failed := make([]string, 0, len(files))
+    for _, file := range files {
+        failed = append(failed, file.Name)
+    }
+    return failed
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Create an empty attachment batch for the repository.
+func newAttachmentBatch(repo string, lg *ledger.Ledger) *attachmentBatch {
+    return &attachmentBatch{
+        repo:   repo,
+        ledger: lg,
+        files:  map[string]Github.ProjFile{},
+        stores: map[string]string{},
+        issues: map[string]string{},
+        stored: map[string]string{},
+        staged: []string{},
+    }
+}
+
+// Download the attachments of a Jira issue which the ledger does not show as
+// having been stored, noting the backend which will store each.
+//  NOTE: an attachment which is too large for any backend or whose download
+//  failed (or did not match its Jira size) is skipped.
+func downloadAttachments(jiraIssue Jira.Issue, lg *ledger.Ledger) []*PreparedAttachment {
+    key   := jiraIssue.Key()
+    entry := lg.Get(key)
+    res   := []*PreparedAttachment{}
+    for _, attach := range jiraIssue.Attachments() {
+        file := convert.AttachmentFile(key, attach.Filename)
+        if entry.HasAttachment(file) {
+            continue
+        }
+        store, err := AttachmentStoreFor(lg.Repo, key, file, int64(attach.Size))
+        if err != nil {
+            logError("%s: ATTACHMENT NOT STORED: %v", key, err)
+            continue
+        }
+        prep, err := stageAttachment(key, attach)
+        if err != nil {
+            logError("%s: ATTACHMENT %q NOT DOWNLOADED: %v", key, attach.Filename, err)
+            continue
+        }
+        prep.Store = store
+        res = append(res, prep)
+    }
+    return res
+}
+
+// Prepare issues concurrently then write them to GitHub in order.
+//  NOTE: if `monitor` is nil then no GitHub updates are made.
+func transferIssues(issues []Jira.Issue, repo string, monitor *ImportMonitor) (stats transferStats) {
+    var lg *ledger.Ledger
+    var batch *attachmentBatch
+    if monitor != nil {
+        lg    = monitor.ledger
+        batch = newAttachmentBatch(repo, lg)
+        defer githubWriter.Do(batch.Flush)
+    }
+
+    // Hand out issues to be prepared, limiting the number in progress.
+    window := make(chan struct{}, PIPELINE_BUFFER)
+    tasks  := make(chan int)
+    go func() {
+        defer close(tasks)
+        for seq := range issues {
+            window <- struct{}{}
+            tasks  <- seq
+        }
+    }()
+
+    // Prepare issues with a pool of workers.
+    prepared := make(chan *PreparedIssue, PIPELINE_BUFFER)
+    var workers sync.WaitGroup
+    for range PIPELINE_WORKERS {
+        workers.Add(1)
+        go func() {
+            defer workers.Done()
+            for seq := range tasks {
+                prepared <- prepareStage(issues[seq], seq, lg)
+            }
+        }()
+    }
+    go func() {
+        workers.Wait()
+        close(prepared)
+    }()
+
+    // Restore the original order and write each issue.
+    waiting := map[int]*PreparedIssue{}
+    next    := 0
+    for prep := range prepared {
+        waiting[prep.Seq] = prep
+        for prep = waiting[next]; prep != nil; prep = waiting[next] {
+            delete(waiting, next)
+            next++
+            if writeStage(prep, repo, monitor, batch) {
+                if stats.first == "" { stats.first = prep.Key }
+                stats.last = prep.Key
+                stats.total++
+            } else if prep.Skip {
+                stats.skipped++
+            }
+            <-window
+        }
+    }
+    return
+}
+
+// Prepare an issue unless the ledger shows it does not need to be transferred.
+//  NOTE: the attachments of an issue which was transferred are downloaded if
+//  they were not stored (e.g. because the previous run was interrupted before
+//  its last attachment batch was stored).
+func prepareStage(jiraIssue Jira.Issue, seq int, lg *ledger.Ledger) *PreparedIssue {
+    key := jiraIssue.Key()
+    if entry := lg.Get(key); entry.Done() || entry.Pending() {
+        prep := &PreparedIssue{Seq: seq, Key: key, Skip: true}
+        if entry.Pending() {
+            prep.Watch = entry.ImportID
+        }
+        if !FakeTransfer {
+            prep.Attachments = downloadAttachments(jiraIssue, lg)
+        }
+        return prep
+    }
+    prep := PrepareIssue(jiraIssue, lg)
+    prep.Seq = seq
+    return prep
+}
+
+// Send a prepared issue to GitHub through the GitHub writer stage.
+//  NOTE: returns false for an issue which was skipped.
+//  NOTE: if `batch` is nil then attachments are stored immediately.
+func writeStage(prep *PreparedIssue, repo string, monitor *ImportMonitor, batch *attachmentBatch) bool {
+    logConversion(prep)
+    switch {
+        case prep.Skip:
+            if prep.Watch != 0 {
+                monitor.Watch(prep.Key, prep.Watch)
+            }
+            if (batch != nil) && (len(prep.Attachments) > 0) {
+                githubWriter.Do(func() { batch.Add(prep, false) })
+            }
+            return false
+        case FakeTransfer || (monitor == nil):
+            return true
+        case repo == "":
+            logError("NO REPO DESTINATION FOR ISSUE %q", prep.Key)
+            return false
+    }
+    monitor.Reserve()
+    ok := false
+    githubWriter.Do(func() {
+        ok = WriteIssue(prep, repo, monitor, batch)
+    })
+    return ok
+}
@@ -0,0 +1,375 @@
+// plan.go
+//
+// Generate a transfer plan for review without making any GitHub updates.
+//
+// For each Jira project ("PROJ") the full Jira fetch and conversion is run and
+// the results are written to "tmp/plan/PROJ.json" and "tmp/plan/PROJ.md".
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+// Path relative to project root of the directory holding plan files.
+const PLAN_DIR = "tmp/plan"
+
+// Typical time for GitHub to resolve an issue import, used to estimate the
+// number of status checks made for each import (one every IMPORT_POLL_DELAY).
+const PLAN_IMPORT_TIME = 6 * time.Second
+
+// Names of the GitHub API requests counted in a plan.
+const (
+    CallRepoGet         = "repository.get"
+    CallMilestoneList   = "milestone.list"
+    CallMilestone       = "milestone.create"
+    CallLabelList       = "label.list"
+    CallLabel           = "label.create"
+    CallAssigneeCheck   = "assignee.check"
+    CallAttachment      = "attachment.blob"
+    CallAttachCommit    = "attachment.commit"
+    CallAttachAsset     = "attachment.asset"
+    CallImport          = "issue.import"
+    CallImportStatus    = "issue.import.status"
+    CallIssueType       = "issue.type"
+    CallStateReason     = "issue.close"
+    CallProject         = "project.create"
+    CallProjectItem     = "project.item"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Expected number of GitHub API requests by kind.
+type ApiCalls map[string]int
+
+// The planned transfer of a Jira project.
+type ProjectPlan struct {
+    Project     string          `json:"project"`
+    Name        string          `json:"name"`
+    Repo        string          `json:"repo"`
+    ProjRepo    bool            `json:"project_repo"`
+    Milestones  []string        `json:"milestones,omitempty"`
+    Boards      []string        `json:"boards,omitempty"`
+    Issues      []*IssuePlan    `json:"issues"`
+    ApiCalls    ApiCalls        `json:"api_calls"`
+}
+
+// The planned transfer of a Jira issue.
+type IssuePlan struct {
+    Key         string              `json:"key"`
+    Repo        string              `json:"repo"`
+    Title       string              `json:"title"`
+    Body        string              `json:"body"`
+    Labels      []string            `json:"labels,omitempty"`
+    Assignee    string              `json:"assignee,omitempty"`
+    Closed      bool                `json:"closed"`
+    StateReason string              `json:"state_reason,omitempty"`
+    Milestone   string              `json:"milestone,omitempty"`
+    IssueType   string              `json:"issue_type,omitempty"`
+    Comments    int                 `json:"comments"`
+    Attachments []*AttachmentPlan   `json:"attachments,omitempty"`
+    ApiCalls    ApiCalls            `json:"api_calls"`
+}
+
+// The planned transfer of a Jira attachment.
+type AttachmentPlan struct {
+    File        string  `json:"file"`
+    Size        int     `json:"size"`
+    Store       string  `json:"store,omitempty"`
+    Error       string  `json:"error,omitempty"`
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Generate transfer plans for the given Jira projects.
+//  NOTE: projectKeys must have ALL_PROJECTS or a list of Jira project keys.
+func PlanAll(projectKeys ...string) {
+    projIssues := ValidateProjectKeys(projectKeys...)
+    projectKeys = util.MapKeys(projIssues)
+    all   := slices.Contains(projectKeys, ALL_PROJECTS)
+    count := 0
+    for _, project := range Jira.MainClient().GetProjects() {
+        if proj := project.Key(); all || slices.Contains(projectKeys, proj) {
+            minMax := []string{}
+            if !all {
+                minMax = projIssues[proj]
+            }
+            plan := PlanProject(project, minMax)
+            if writePlan(plan) {
+                count++
+            }
+        }
+    }
+    ledger.CloseAll()
+    logSummary("PROJECTS PLANNED: %d", count)
+}
+
+// Generate the transfer plan for the given Jira project.
+//  NOTE: never returns nil
+func PlanProject(project *Jira.Project, minMax []string) *ProjectPlan {
+    var min, max string
+    switch len(minMax) {
+        case 0:  min, max = "", ""
+        case 1:  min, max = minMax[0], ""
+        default: min, max = minMax[0], minMax[1]
+    }
+    repo, projRepo := projectRepository(project.Key())
+    plan := &ProjectPlan{
+        Project:  project.Key(),
+        Name:     project.Name(),
+        Repo:     repo,
+        ProjRepo: projRepo,
+        Issues:   []*IssuePlan{},
+        ApiCalls: ApiCalls{},
+    }
+    if projRepo {
+        plan.ApiCalls[CallRepoGet]++
+    }
+    titles := map[string]string{}
+    for _, version := range project.Versions() {
+        titles[convert.VersionSource(version.Name)] = convert.VersionMilestone(version).Title
+    }
+    for _, epic := range project.Epics() {
+        titles[convert.EpicSource(epic.Key())] = convert.EpicMilestone(epic).Title
+    }
+    if len(titles) > 0 {
+        plan.Milestones = util.MapValues(titles)
+        slices.Sort(plan.Milestones)
+        plan.ApiCalls[CallMilestoneList]++
+        plan.ApiCalls[CallMilestone] += len(titles)
+    }
+    assignees := map[string]bool{}
+    labels    := map[string]bool{}
+    for _, issue := range project.GetIssues(min, max) {
+        item := PlanIssue(issue, repo)
+        item.Milestone = titles[item.Milestone]
+        for _, label := range convert.SchemeLabels(issue) {
+            labels[strings.ToLower(label.Name)] = true
+        }
+        if item.Assignee != "" {
+            if assignees[item.Assignee] {
+                delete(item.ApiCalls, CallAssigneeCheck)
+            }
+            assignees[item.Assignee] = true
+        }
+        plan.ApiCalls.Add(item.ApiCalls)
+        plan.Issues = append(plan.Issues, item)
+    }
+    if files := plan.ApiCalls[CallAttachment]; files > 0 {
+        // At most blobs; text files do not need a separate blob.  At least
+        // commits; a batch is also stored when it reaches ATTACH_BATCH_BYTES.
+        // Each commit gets the branch and its head commit, creates a tree and a
+        // commit, and updates the branch.
+        commits := (files + ATTACH_BATCH_FILES - 1) / ATTACH_BATCH_FILES
+        plan.ApiCalls[CallAttachCommit] += 5 * commits
+    }
+    if len(labels) > 0 {
+        // At most; synthesized labels already in the repository are not created.
+        plan.ApiCalls[CallLabelList]++
+        plan.ApiCalls[CallLabel] += len(labels)
+    }
+    for _, board := range project.Boards(convert.ProjectFieldSources()...) {
+        // At most; issues on the board from other projects may not be transferred.
+        plan.Boards = append(plan.Boards, convert.BoardTitle(board))
+        plan.ApiCalls[CallProject]++
+        plan.ApiCalls[CallProjectItem] += len(board.Issues)
+    }
+    return plan
+}
+
+// Generate the transfer plan for the given Jira issue.
+//  NOTE: Milestone is the Jira source of the milestone, not its title.
+//  NOTE: never returns nil
+func PlanIssue(jiraIssue Jira.Issue, repo string) *IssuePlan {
+    key := jiraIssue.Key()
+    issue, comments, _, _ := convertIssue(jiraIssue, nil)
+    plan := &IssuePlan{
+        Key:         key,
+        Repo:        repo,
+        Title:       issue.Title,
+        Body:        issue.Body,
+        Labels:      issue.Labels,
+        Assignee:    issue.GetAssignee(),
+        Closed:      issue.GetClosed(),
+        StateReason: convert.StateReason(jiraIssue),
+        Milestone:   convert.MilestoneSource(jiraIssue),
+        IssueType:   convert.IssueType(jiraIssue),
+        Comments:    len(comments),
+        ApiCalls:    ApiCalls{},
+    }
+    for _, attach := range jiraIssue.Attachments() {
+        file := convert.AttachmentFile(key, attach.Filename)
+        item := &AttachmentPlan{File: file, Size: attach.Size}
+        plan.Attachments = append(plan.Attachments, item)
+        store, err := AttachmentStoreFor(repo, key, file, int64(attach.Size))
+        if err != nil {
+            item.Error = err.Error()
+            continue
+        }
+        item.Store = store.Name()
+        switch item.Store {
+            case STORE_REPO, STORE_CENTRAL:
+                plan.ApiCalls[CallAttachment]++
+            case STORE_RELEASE:
+                plan.ApiCalls[CallAttachAsset]++
+        }
+    }
+    if plan.Assignee != "" {
+        plan.ApiCalls[CallAssigneeCheck]++
+    }
+    plan.ApiCalls[CallImport]++
+    plan.ApiCalls[CallImportStatus] += importPolls()
+    if plan.IssueType != "" {
+        plan.ApiCalls[CallIssueType]++
+    }
+    for _, prop := range issueProperties(jiraIssue) {
+        if prop.Name == ledger.PropertyStateReason {
+            plan.ApiCalls[CallStateReason]++
+        }
+    }
+    return plan
+}
+
+// ============================================================================
+// Methods
+// ============================================================================
+
+// Accumulate request counts.
+func (c ApiCalls) Add(other ApiCalls) {
+    for kind, count := range other {
+        c[kind] += count
+    }
+}
+
+// The total number of requests.
+func (c ApiCalls) Total() int {
+    total := 0
+    for _, count := range c {
+        total += count
+    }
+    return total
+}
+
+// Render request counts in a consistent order.
+func (c ApiCalls) String() string {
+    kinds := util.MapKeys(c)
+    slices.Sort(kinds)
+    res := make([]string, 0, len(kinds))
+    for _, kind := range kinds {
+        res = append(res, fmt.Sprintf("%s: %d", kind, c[kind]))
+    }
+    return strings.Join(res, ", ")
+}
+
+// Render the plan as JSON.
+func (p *ProjectPlan) Json() string {
+    bytes, err := json.MarshalIndent(p, "", "  ")
+    if err != nil {
+        logError("%s PLAN: %v", p.Project, err)
+    }
+    return string(bytes)
+}
+
+// Render the plan as Markdown for human review.
+func (p *ProjectPlan) Markdown() string {
+    size := 0
+    for _, issue := range p.Issues {
+        for _, attach := range issue.Attachments {
+            size += attach.Size
+        }
+    }
+    var b strings.Builder
+    fmt.Fprintf(&b, "# Transfer plan for Jira project %s (%s)\n\n", p.Project, p.Name)
+    fmt.Fprintf(&b, "| | |\n|---|---|\n")
+    fmt.Fprintf(&b, "| Repository | %s/%s |\n", Github.ORG, p.Repo)
+    fmt.Fprintf(&b, "| Issues | %d |\n", len(p.Issues))
+    fmt.Fprintf(&b, "| Milestones | %d |\n", len(p.Milestones))
+    fmt.Fprintf(&b, "| Project boards | %d |\n", len(p.Boards))
+    fmt.Fprintf(&b, "| Attachment bytes | %d |\n", size)
+    fmt.Fprintf(&b, "| GitHub API requests (estimated) | %d (%s) |\n", p.ApiCalls.Total(), p.ApiCalls)
+    for _, issue := range p.Issues {
+        fmt.Fprintf(&b, "\n## %s\n\n", issue.Key)
+        fmt.Fprintf(&b, "* Title: %s\n", issue.Title)
+        if len(issue.Labels) > 0 {
+            fmt.Fprintf(&b, "* Labels: %s\n", strings.Join(issue.Labels, ", "))
+        }
+        if issue.Assignee != "" {
+            fmt.Fprintf(&b, "* Assignee: %s\n", issue.Assignee)
+        }
+        if issue.StateReason != "" {
+            fmt.Fprintf(&b, "* Closed: %v (%s)\n", issue.Closed, issue.StateReason)
+        } else {
+            fmt.Fprintf(&b, "* Closed: %v\n", issue.Closed)
+        }
+        if issue.Milestone != "" {
+            fmt.Fprintf(&b, "* Milestone: %s\n", issue.Milestone)
+        }
+        if issue.IssueType != "" {
+            fmt.Fprintf(&b, "* Issue type: %s\n", issue.IssueType)
+        }
+        fmt.Fprintf(&b, "* Comments: %d\n", issue.Comments)
+        for _, attach := range issue.Attachments {
+            if attach.Error != "" {
+                fmt.Fprintf(&b, "* Attachment: %s (%d bytes) NOT STORED: %s\n", attach.File, attach.Size, attach.Error)
+            } else {
+                fmt.Fprintf(&b, "* Attachment: %s (%d bytes) in %s\n", attach.File, attach.Size, attach.Store)
+            }
+        }
+        fmt.Fprintf(&b, "* GitHub API requests: %s\n", issue.ApiCalls)
+        fmt.Fprintf(&b, "\n<details><summary>Body</summary>\n\n%s\n\n</details>\n", issue.Body)
+    }
+    return b.String()
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Write JSON and Markdown plan files for the project.
+func writePlan(plan *ProjectPlan) bool {
+    dir := filepath.Join(util.RootPath(), PLAN_DIR)
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        logError("%s PLAN: %v", plan.Project, err)
+        return false
+    }
+    base  := filepath.Join(dir, plan.Project)
+    files := map[string]string{
+        base + ".json": plan.Json(),
+        base + ".md":   plan.Markdown(),
+    }
+    for file, content := range files {
+        if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+            logError("%s PLAN: %v", plan.Project, err)
+            return false
+        }
+    }
+    logSummary("%s (%s) ISSUES PLANNED: %d -> %s.{json,md}", plan.Project, plan.Name, len(plan.Issues), base)
+    return true
+}
+
+// The expected number of status checks made while an import is pending.
+//  NOTE: imports which take GitHub longer than PLAN_IMPORT_TIME need more.
+func importPolls() int {
+    return int((PLAN_IMPORT_TIME + IMPORT_POLL_DELAY - 1) / IMPORT_POLL_DELAY)
+}
@@ -0,0 +1,121 @@
+// property.go
+//
+// GitHub issue properties which are applied after import.
+//
+// Some properties of a GitHub issue cannot be given in an issue import request.
+// The values wanted for each issue are recorded in the ledger and applied at
+// the end of a "-transfer" or "-sync" run to the issues which have been
+// created:
+//
+// * The GitHub issue type matching the Jira issue type.
+// * The reason a closed issue was closed, other than "completed" (the reason
+//   given to an issue which is imported as closed).
+//
+// A property which could not be applied is tried again by a later run.
+
+package main
+
+import (
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The GitHub issue properties to be applied for a Jira issue.
+func issueProperties(jiraIssue Jira.Issue) []ledger.Property {
+    res := []ledger.Property{}
+    if name := convert.IssueType(jiraIssue); name != "" {
+        res = append(res, ledger.Property{Name: ledger.PropertyIssueType, Value: name})
+    }
+    switch reason := convert.StateReason(jiraIssue); {
+        case (reason == "") || (reason == convert.STATE_COMPLETED):
+            // An issue is imported as closed with the "completed" reason.
+        case (reason == convert.STATE_DUPLICATE) && (convert.DuplicateOf(jiraIssue) != ""):
+            // The issue is closed as a duplicate of its original by linkAll().
+        case reason == convert.STATE_DUPLICATE:
+            // GitHub requires the original of a duplicate.
+            res = append(res, ledger.Property{Name: ledger.PropertyStateReason, Value: convert.STATE_NOT_PLANNED})
+        default:
+            res = append(res, ledger.Property{Name: ledger.PropertyStateReason, Value: reason})
+    }
+    return res
+}
+
+// Apply pending GitHub issue properties for the issues of every ledger used in
+// this run.
+func applyAll() {
+    if FakeTransfer { return }
+    prepared := false
+    for _, lg := range ledger.Opened() {
+        if !prepared && hasPendingProperties(lg) {
+            githubWriter.Do(prepareIssueTypes)
+            prepared = true
+        }
+        if count := applyProperties(lg); count > 0 {
+            logSummary("%s ISSUE PROPERTIES APPLIED: %d", lg.Project, count)
+        }
+    }
+}
+
+// Indicate whether any transferred issue of the ledger has pending properties.
+func hasPendingProperties(lg *ledger.Ledger) bool {
+    for _, entry := range lg.Entries() {
+        if entry.Done() && (len(entry.PendingProperties()) > 0) {
+            return true
+        }
+    }
+    return false
+}
+
+// Apply the pending properties of the ledger's transferred issues.
+func applyProperties(lg *ledger.Ledger) (count int) {
+    for _, entry := range lg.Entries() {
+        if !entry.Done() {
+            continue
+        }
+        for _, prop := range entry.PendingProperties() {
+            githubWriter.Do(func() {
+                if applyProperty(lg, entry, prop) {
+                    lg.PropertyApplied(entry.Key, prop)
+                    count++
+                }
+            })
+        }
+    }
+    return
+}
+
+// Apply a property to the GitHub issue of a transferred Jira issue.
+//  NOTE: returns false if the property was not applied.
+func applyProperty(lg *ledger.Ledger, entry *ledger.Entry, prop ledger.Property) bool {
+    client := Github.MainClient()
+    switch prop.Name {
+        case ledger.PropertyIssueType:
+            return Github.SetIssueType(client, Github.ORG, lg.Repo, entry.Issue, prop.Value)
+        case ledger.PropertyStateReason:
+            reason := prop.Value
+            if reason == convert.STATE_DUPLICATE {
+                reason = convert.STATE_NOT_PLANNED // Recorded by an earlier run.
+            }
+            return Github.CloseIssue(client, Github.ORG, lg.Repo, entry.Issue, reason)
+        default:
+            logError("%s: UNKNOWN ISSUE PROPERTY %q", entry.Key, prop.Name)
+            return false
+    }
+}
+
+// Create the organization issue types in convert.GITHUB_ISSUE_TYPES which do
+// not already exist.
+func prepareIssueTypes() {
+    for _, issueType := range convert.GITHUB_ISSUE_TYPES {
+        if !Github.CreateIssueType(Github.ORG, issueType) {
+            logError("%s: ISSUE TYPE %q NOT CREATED", Github.ORG, issueType.Name)
+        }
+    }
+}
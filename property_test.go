@@ -0,0 +1,76 @@
+// property_test.go
+
+package main
+
+import (
+	"testing"
+
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Jira"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestIssuePropertiesStateReason(t *testing.T) {
+    const fn = "issueProperties"
+
+	type testCase struct {
+		name       string
+		resolution string
+		original   Jira.IssueKey
+		want       string
+	}
+
+    Case := func(idx int, resolution string, original Jira.IssueKey, want string) testCase {
+        return testCase{test.CaseName(fn, idx), resolution, original, want}
+    }
+
+	tests := []testCase{
+        Case(0, "Fixed",     "",       ""),
+        Case(1, "Won't Fix", "",       convert.STATE_NOT_PLANNED),
+        Case(2, "Duplicate", "PROJ-1", ""),
+        Case(3, "Duplicate", "",       convert.STATE_NOT_PLANNED),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := ""
+            for _, prop := range issueProperties(testClosedIssue(tt.resolution, tt.original)) {
+                if prop.Name == ledger.PropertyStateReason {
+                    got = prop.Value
+                }
+            }
+            if got != tt.want {
+                t.Errorf("%s() state reason = %q, want %q", fn, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Internal functions - test support
+// ============================================================================
+
+// A closed Jira issue with the given resolution which, if `original` is not
+// blank, "duplicates" that issue.
+//  NOTE: the original need not have been transferred; the issue is closed as
+//  its duplicate by linkAll() once it has been.
+func testClosedIssue(resolution string, original Jira.IssueKey) Jira.Issue {
+    fields := &jira.IssueFields{
+        Status:     &jira.Status{StatusCategory: jira.StatusCategory{Key: "done"}},
+        Resolution: &jira.Resolution{Name: resolution},
+    }
+    if original != "" {
+        fields.IssueLinks = []*jira.IssueLink{{
+            Type:         jira.IssueLinkType{Name: convert.JIRA_LINK_DUPLICATE, Outward: "duplicates"},
+            OutwardIssue: &jira.Issue{Key: original},
+        }}
+    }
+    return *Jira.NewIssueType(&Jira.Client{}, &jira.Issue{Key: "PROJ-2", Fields: fields})
+}
@@ -0,0 +1,132 @@
+// reference.go
+//
+// Links between transferred issues.
+//
+// Jira issue keys and browse URLs in issue and comment bodies are converted to
+// links to the matching GitHub issues by looking up the referenced issues in
+// the transfer ledgers.  References to issues which had not been transferred
+// when the text was converted are recorded in the ledger entry of the issue so
+// that its GitHub issue and comments can be fixed up in a second pass.
+
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/ledger"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Give the GitHub URL for a transferred Jira issue or, if `comment` is not
+// blank, for one of its comments.
+//  NOTE: returns blank if the issue has not been transferred.
+func resolveReference(key Jira.IssueKey, comment string) string {
+    repo, entry := transferredIssue(key)
+    if entry == nil {
+        return ""
+    }
+    if id := entry.Comment(comment); id != 0 {
+        return Github.CommentURL(Github.ORG, repo, entry.Issue, id)
+    }
+    return Github.IssueURL(Github.ORG, repo, entry.Issue)
+}
+
+// Give the GitHub repository and ledger entry for a transferred Jira issue.
+//  NOTE: returns a nil entry if the issue has not been transferred.
+//  NOTE: a plan only looks up ledgers so that their files are left untouched.
+func transferredIssue(key Jira.IssueKey) (string, *ledger.Entry) {
+    proj, _, _ := strings.Cut(key, "-")
+    if !ledger.Exists(proj) {
+        return "", nil
+    }
+    repo, _ := projectRepository(proj)
+    var lg *ledger.Ledger
+    if Mode == ModePlan {
+        lg = ledger.Lookup(proj)
+    } else {
+        lg = ledger.Open(proj, repo)
+    }
+    entry := lg.Get(key)
+    if !entry.Done() || (entry.Issue == 0) {
+        return "", nil
+    }
+    if lg.Repo != "" {
+        repo = lg.Repo
+    }
+    return repo, entry
+}
+
+// Convert references to Jira issues, adding the keys of references which
+// could not be resolved to `unresolved`.
+func convertReferences(text string, unresolved *[]string) string {
+    text, keys := convert.References(text, resolveReference)
+    for _, key := range keys {
+        if !slices.Contains(*unresolved, key) {
+            *unresolved = append(*unresolved, key)
+        }
+    }
+    return text
+}
+
+// Fix up references in the GitHub issues of every ledger used in this run.
+func fixAllReferences() {
+    if FakeTransfer { return }
+    for _, lg := range ledger.Opened() {
+        if count := fixReferences(lg); count > 0 {
+            logSummary("%s ISSUES WITH REFERENCES FIXED: %d", lg.Project, count)
+        }
+    }
+}
+
+// Update the GitHub issues of the ledger's project which refer to Jira issues
+// that have been transferred since the references were converted.
+func fixReferences(lg *ledger.Ledger) (count int) {
+    resolvable := func(key string) bool {
+        return resolveReference(key, "") != ""
+    }
+    for _, entry := range lg.Entries() {
+        if entry.Done() && slices.ContainsFunc(entry.Unresolved, resolvable) {
+            githubWriter.Do(func() {
+                if fixIssueReferences(lg, entry) {
+                    count++
+                }
+            })
+        }
+    }
+    return
+}
+
+// Convert the references remaining in a GitHub issue and its comments.
+//  NOTE: returns false if no change was made.
+func fixIssueReferences(lg *ledger.Ledger, entry *ledger.Entry) bool {
+    client := Github.MainClient()
+    issue  := Github.GetIssue(client, Github.ORG, lg.Repo, entry.Issue)
+    if issue == nil {
+        logError("%s: GITHUB ISSUE %d NOT FOUND", entry.Key, entry.Issue)
+        return false
+    }
+    changed    := false
+    unresolved := []string{}
+    if body := convertReferences(issue.Body(), &unresolved); body != issue.Body() {
+        if issue.EditFrom(issue.Title(), body) != nil {
+            changed = true
+        }
+    }
+    for _, comment := range issue.Comments() {
+        if body := convertReferences(comment.Body(), &unresolved); body != comment.Body() {
+            if comment.EditFrom(body) != nil {
+                changed = true
+            }
+        }
+    }
+    lg.SetUnresolved(entry.Key, unresolved)
+    return changed
+}
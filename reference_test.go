@@ -0,0 +1,70 @@
+// reference_test.go
+
+package main
+
+import (
+	"testing"
+
+	"lib.virginia.edu/agita/ledger"
+	"lib.virginia.edu/agita/test"
+
+	"lib.virginia.edu/agita/Github"
+)
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestResolveReference(t *testing.T) {
+    const fn = "resolveReference"
+
+	type args struct {
+		key     string
+		comment string
+	}
+	type testCase struct {
+		name string
+		args args
+		want string
+	}
+
+    const (
+        project = "TESTREF"
+        repo    = "test-reference-repo"
+        number  = 42
+        jiraID  = "10001"
+        id      = int64(987654)
+    )
+    ledger.LEDGER_DIR = t.TempDir()
+    defer ledger.CloseAll()
+    lg := ledger.Open(project, repo)
+    lg.Update(project + "-1", func(e *ledger.Entry) {
+        e.Status, e.Issue = ledger.StatusImported, number
+    })
+    lg.AddComment(project + "-1", jiraID, id)
+    lg.Update(project + "-2", func(e *ledger.Entry) {
+        e.Status, e.ImportID = ledger.StatusPending, 1
+    })
+
+    issueURL   := Github.IssueURL(Github.ORG, repo, number)
+    commentURL := Github.CommentURL(Github.ORG, repo, number, id)
+    Case       := func(idx int, key, comment, want string) testCase {
+        return testCase{test.CaseName(fn, idx), args{key, comment}, want}
+    }
+
+	tests := []testCase{
+        Case(0, project + "-1", jiraID,  commentURL),
+        Case(1, project + "-1", "",      issueURL),
+        Case(2, project + "-1", "10002", issueURL),
+        Case(3, project + "-2", jiraID,  ""),
+        Case(4, project + "-3", "",      ""),
+        Case(5, "NOLEDGER-1",   "",      ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := resolveReference(tt.args.key, tt.args.comment); got != tt.want {
+                t.Errorf("%s() = %q, want %q", fn, got, tt.want)
+            }
+		})
+	}
+}
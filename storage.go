@@ -0,0 +1,390 @@
+// storage.go
+//
+// Backends which store Jira attachments for the GitHub issues which refer to
+// them.
+//
+// Each attachment is stored by the backend of the first of ATTACH_STORE_RULES
+// which applies to its Jira project and size:
+//
+// * STORE_REPO    - the ATTACH_DIR of the project repository (to 100 MB).
+// * STORE_RELEASE - assets of the ATTACH_RELEASE release of the project
+//                   repository (to 2 GB).
+// * STORE_CENTRAL - the ATTACH_DIR of ATTACH_CENTRAL_REPO, which is shared by
+//                   all projects (to 100 MB).
+// * STORE_LOCAL   - ATTACH_ARCHIVE_DIR on this machine, laid out like the
+//                   project repository, for offline archives.
+//
+// References to an attachment in GitHub issues and comments are links to the
+// copy held by the backend which stores it.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lib.virginia.edu/agita/convert"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// A place where attachment files are stored.
+type AttachmentStore interface {
+
+    // The name of the backend in ATTACH_STORE_RULES and ATTACH_MANIFEST.
+    Name() string
+
+    // The largest file which can be stored (or 0 if there is no limit).
+    MaxSize() int64
+
+    // The relative URL of a stored file from an issue in the project
+    // repository.
+    Url(file string) string
+
+    // Store files for the project repository.
+    Store(repo, message string, files []Github.ProjFile) error
+
+    // Check the stored files for the project repository.
+    //  NOTE: returns the names of the files which are missing or differ.
+    Verify(repo string, files []Github.ProjFile) []string
+}
+
+// Selects the backend for the attachments of a Jira project or of a class of
+// file sizes.
+type StoreRule struct {
+    Project string  // Jira project key or blank for any project.
+    MaxSize int64   // Largest attachment size or 0 for any size.
+    Store   string  // Backend name.
+}
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+// Attachment storage backend names.
+const (
+    STORE_REPO    = "repo"
+    STORE_RELEASE = "release"
+    STORE_CENTRAL = "central"
+    STORE_LOCAL   = "local"
+)
+
+// Tag of the release of a project repository whose assets are attachments.
+const ATTACH_RELEASE = "jira-attachments"
+
+// Name of the repository where STORE_CENTRAL keeps attachments.
+const ATTACH_CENTRAL_REPO = "jira-attachments"
+
+// If true, the repository where STORE_CENTRAL keeps attachments is created as
+// a private repository.  Links to its files from the issues of a public
+// repository only work for those with access to it, so a warning is logged
+// when its files are stored for a public repository.
+const ATTACH_CENTRAL_PRIVATE = true
+
+// Path relative to project root of the directory where STORE_LOCAL keeps
+// attachments, in a subdirectory for each project repository.
+const ATTACH_ARCHIVE_DIR = "tmp/archive"
+
+// ============================================================================
+// Variables
+// ============================================================================
+
+// Rules selecting the backend for each attachment; the first rule matching the
+// Jira project whose MaxSize (and that of its backend) allows the attachment
+// is applied.  For example, to keep one project's attachments offline, to put
+// files up to 10 MB in the shared repository and larger files in releases:
+//
+//  {Project: "ARCHIVE", Store: STORE_LOCAL},
+//  {MaxSize: 10 * 1024 * 1024, Store: STORE_CENTRAL},
+//  {Store: STORE_RELEASE},
+//
+var ATTACH_STORE_RULES = []StoreRule{
+    {Store: STORE_REPO},
+    {Store: STORE_RELEASE},
+}
+
+// The attachment storage backends by name.
+var attachmentStores = map[string]AttachmentStore{
+    STORE_REPO:    repoStore{},
+    STORE_RELEASE: releaseStore{tag: ATTACH_RELEASE},
+    STORE_CENTRAL: &centralStore{repo: ATTACH_CENTRAL_REPO, private: ATTACH_CENTRAL_PRIVATE},
+    STORE_LOCAL:   localStore{dir: ATTACH_ARCHIVE_DIR},
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// The backend which stores an attachment of the given size for a Jira issue.
+//  NOTE: returns *Github.FileTooLargeError if no backend can store the file.
+func AttachmentStoreFor(repo, key, file string, size int64) (AttachmentStore, error) {
+    proj, _, _ := strings.Cut(key, "-")
+    limit := int64(0)
+    for _, rule := range ATTACH_STORE_RULES {
+        store := AttachmentStoreNamed(rule.Store)
+        if (store == nil) || ((rule.Project != "") && (rule.Project != proj)) {
+            continue
+        }
+        most := store.MaxSize()
+        if (rule.MaxSize > 0) && ((most == 0) || (rule.MaxSize < most)) {
+            most = rule.MaxSize
+        }
+        if (most == 0) || (size <= most) {
+            return store, nil
+        }
+        limit = max(limit, most)
+    }
+    return nil, &Github.FileTooLargeError{Repo: repo, File: file, Size: size, Limit: limit}
+}
+
+// The backend with the given name.
+//  NOTE: returns nil if there is no such backend.
+func AttachmentStoreNamed(name string) AttachmentStore {
+    return attachmentStores[name]
+}
+
+// The backend which keeps the ATTACH_MANIFEST of the project repository of a
+// Jira project.  This is STORE_LOCAL if every one of ATTACH_STORE_RULES which
+// applies to the project selects STORE_LOCAL (so that an offline archive makes
+// no GitHub updates) and STORE_REPO otherwise.
+func ManifestStoreFor(proj string) AttachmentStore {
+    local := false
+    for _, rule := range ATTACH_STORE_RULES {
+        if (rule.Project != "") && (rule.Project != proj) {
+            continue
+        } else if rule.Store != STORE_LOCAL {
+            return AttachmentStoreNamed(STORE_REPO)
+        }
+        local = true
+    }
+    if local {
+        return AttachmentStoreNamed(STORE_LOCAL)
+    }
+    return AttachmentStoreNamed(STORE_REPO)
+}
+
+// ============================================================================
+// Internal types
+// ============================================================================
+
+// Attachments in the ATTACH_DIR of the project repository.
+type repoStore struct{}
+
+// Attachments as assets of a release of the project repository.
+type releaseStore struct {
+    tag string
+}
+
+// Attachments in the ATTACH_DIR of a repository shared by all projects.
+type centralStore struct {
+    repo    string
+    private bool            // The repository is private.
+    ready   bool            // The repository is known to exist.
+    checked map[string]bool // Project repositories checked for visibility.
+}
+
+// Attachments in a local directory.
+type localStore struct {
+    dir string
+}
+
+// ============================================================================
+// Internal methods - repoStore
+// ============================================================================
+
+func (repoStore) Name() string {
+    return STORE_REPO
+}
+
+func (repoStore) MaxSize() int64 {
+    return Github.MAX_FILE_SIZE
+}
+
+func (repoStore) Url(file string) string {
+    return convert.AttachmentUrl(file)
+}
+
+func (repoStore) Store(repo, message string, files []Github.ProjFile) error {
+    return Github.CreateProjAttachments(nil, repo, message, files)
+}
+
+func (repoStore) Verify(repo string, files []Github.ProjFile) []string {
+    return Github.VerifyProjAttachments(nil, repo, files)
+}
+
+// ============================================================================
+// Internal methods - releaseStore
+// ============================================================================
+
+func (s releaseStore) Name() string {
+    return STORE_RELEASE
+}
+
+func (s releaseStore) MaxSize() int64 {
+    return Github.MAX_ASSET_SIZE
+}
+
+func (s releaseStore) Url(file string) string {
+    return "../" + Github.ReleaseAssetPath(s.tag, file)
+}
+
+func (s releaseStore) Store(repo, _ string, files []Github.ProjFile) error {
+    return Github.CreateReleaseAssets(nil, repo, s.tag, files)
+}
+
+func (s releaseStore) Verify(repo string, files []Github.ProjFile) []string {
+    return Github.VerifyReleaseAssets(nil, repo, s.tag, files)
+}
+
+// ============================================================================
+// Internal methods - centralStore
+// ============================================================================
+
+func (s *centralStore) Name() string {
+    return STORE_CENTRAL
+}
+
+func (s *centralStore) MaxSize() int64 {
+    return Github.MAX_FILE_SIZE
+}
+
+func (s *centralStore) Url(file string) string {
+    return "../../" + s.repo + "/blob/main/" + Github.ATTACH_DIR + "/" + url.PathEscape(file) + "?raw=true"
+}
+
+//  NOTE: creates the shared repository if necessary.
+func (s *centralStore) Store(repo, message string, files []Github.ProjFile) error {
+    if !s.ready {
+        central := Github.GetAttachRepo(nil, s.repo, s.private)
+        if central == nil {
+            return fmt.Errorf("no repository %q", s.repo)
+        }
+        s.private = central.Repo().GetPrivate()
+        s.checked = map[string]bool{}
+        s.ready   = true
+    }
+    if s.private && !s.checked[repo] {
+        s.checked[repo] = true
+        if proj := Github.GetRepository(Github.MainClient(), Github.ORG, repo, true); (proj != nil) && !proj.Repo().GetPrivate() {
+            logWarning("%s: PUBLIC REPOSITORY ATTACHMENTS STORED IN PRIVATE REPOSITORY %q", repo, s.repo)
+        }
+    }
+    return Github.CreateProjAttachments(nil, s.repo, message, files)
+}
+
+func (s *centralStore) Verify(_ string, files []Github.ProjFile) []string {
+    return Github.VerifyProjAttachments(nil, s.repo, files)
+}
+
+// ============================================================================
+// Internal methods - localStore
+// ============================================================================
+
+func (s localStore) Name() string {
+    return STORE_LOCAL
+}
+
+func (s localStore) MaxSize() int64 {
+    return 0
+}
+
+//  NOTE: the link is valid if the archive is later added to the repository.
+func (s localStore) Url(file string) string {
+    return convert.AttachmentUrl(file)
+}
+
+func (s localStore) Store(repo, _ string, files []Github.ProjFile) error {
+    dir := s.path(repo)
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return err
+    }
+    for _, file := range files {
+        if err := copyFile(file, filepath.Join(dir, file.Name)); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s localStore) Verify(repo string, files []Github.ProjFile) []string {
+    failed := []string{}
+    for _, file := range files {
+        want, wantErr := contentHash(file)
+        have, haveErr := contentHash(Github.ProjFile{Path: filepath.Join(s.path(repo), file.Name)})
+        if (wantErr != nil) || (haveErr != nil) || (have != want) {
+            failed = append(failed, file.Name)
+        }
+    }
+    return failed
+}
+
+// Get the content of a stored file.
+//  NOTE: returns false if there is no such file.
+func (s localStore) read(repo, file string) (string, bool) {
+    data, err := os.ReadFile(filepath.Join(s.path(repo), file))
+    return string(data), (err == nil)
+}
+
+// The directory holding the attachments of the project repository.
+func (s localStore) path(repo string) string {
+    return filepath.Join(util.RootPath(), s.dir, repo, Github.ATTACH_DIR)
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Give the URL of the stored copy of an attachment of a Jira issue.
+//  NOTE: returns blank if no backend can store the attachment.
+func locateAttachment(key Jira.IssueKey, attach *Jira.Attachment) string {
+    file := convert.AttachmentFile(key, attach.Filename)
+    if store, err := AttachmentStoreFor("", key, file, int64(attach.Size)); err == nil {
+        return store.Url(file)
+    }
+    return ""
+}
+
+// Copy file content to the destination through a temporary file which
+// replaces it when done.
+func copyFile(file Github.ProjFile, dst string) error {
+    src, err := file.Open()
+    if err != nil {
+        return err
+    }
+    defer src.Close()
+    temp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst) + ".*")
+    if err != nil {
+        return err
+    }
+    _, err = io.Copy(temp, src)
+    if closeErr := temp.Close(); err == nil {
+        err = closeErr
+    }
+    if err == nil {
+        err = os.Rename(temp.Name(), dst)
+    }
+    if err != nil {
+        os.Remove(temp.Name())
+    }
+    return err
+}
+
+// The SHA-256 hash (as a hex string) of file content.
+func contentHash(file Github.ProjFile) (string, error) {
+    if file.Path != "" {
+        _, hash, err := fileHash(file.Path)
+        return hash, err
+    }
+    _, hash, err := readerHash(strings.NewReader(file.Content))
+    return hash, err
+}
@@ -0,0 +1,227 @@
+// sync.go
+//
+// Apply Jira changes made after a transfer to the matching GitHub issues.
+
+package main
+
+import (
+	"slices"
+	"time"
+
+	"lib.virginia.edu/agita/ledger"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Update GitHub issues and comments from Jira issues and comments which have
+// changed since the last recorded run for each of the given Jira projects.
+//  NOTE: projectKeys must have ALL_PROJECTS or a list of Jira project keys.
+func SyncAll(projectKeys ...string) {
+    projIssues := ValidateProjectKeys(projectKeys...)
+    projectKeys = util.MapKeys(projIssues)
+    all   := slices.Contains(projectKeys, ALL_PROJECTS)
+    count := 0
+    for _, project := range Jira.MainClient().GetProjects() {
+        if proj := project.Key(); all || slices.Contains(projectKeys, proj) {
+            minMax := []string{}
+            if !all {
+                minMax = projIssues[proj]
+            }
+            repo, _ := projectRepository(proj)
+            if SyncProject(project, repo, minMax) {
+                count++
+            }
+        }
+    }
+    fixAllReferences()
+    linkAll()
+    applyAll()
+    ledger.CloseAll()
+    logSummary("PROJECTS SYNCHRONIZED: %d", count)
+    logRateLimits()
+}
+
+// Update GitHub issues and comments from Jira issues and comments of the given
+// project which have changed since the last recorded run.
+//
+// * Jira issues without a GitHub counterpart are transferred.
+// * GitHub issues are updated with the current Jira title and description.
+// * New Jira comments are added to the GitHub issue and changed Jira comments
+//   are updated in place.
+//
+func SyncProject(project *Jira.Project, repo string, minMax []string) bool {
+    var min, max string
+    switch len(minMax) {
+        case 0:  min, max = "", ""
+        case 1:  min, max = minMax[0], ""
+        default: min, max = minMax[0], minMax[1]
+    }
+    lg    := ledger.Open(project.Key(), repo)
+    since := lg.LastRun()
+    if since.IsZero() {
+        logError("%s HAS NOT BEEN TRANSFERRED - USE -transfer FIRST", project.Key())
+        return false
+    }
+    if count := prepareMilestones(project, repo); count > 0 {
+        logSummary("%s MILESTONES CREATED OR UPDATED: %d", project.Key(), count)
+    }
+    start   := time.Now()
+    monitor := NewImportMonitor(lg, repo)
+    created, updated, unchanged := 0, 0, 0
+    for _, issue := range project.GetIssuesSince(min, max, since) {
+        key   := issue.Key()
+        entry := lg.Get(key)
+        switch {
+            case entry.Pending():
+                monitor.Watch(key, entry.ImportID)
+            case !entry.Done():
+                if TransferIssue(issue, repo, monitor) {
+                    created++
+                }
+            case SyncIssue(issue, repo, lg):
+                updated++
+            default:
+                unchanged++
+        }
+    }
+    reportFailed(project, monitor)
+    lg.SetLastRun(start)
+    if count := prepareBoards(project, repo); count > 0 {
+        logSummary("%s ISSUES PLACED ON PROJECT BOARDS: %d", project.Key(), count)
+    }
+    logSummary("%s (%s) SINCE %s ISSUES CREATED: %d, UPDATED: %d, UNCHANGED: %d", project.Key(), project.Name(), since.Format(time.RFC3339), created, updated, unchanged)
+    return (created + updated) > 0
+}
+
+// Update the GitHub issue and comments from a transferred Jira issue.
+//  NOTE: GitHub is not consulted if the converted content matches the hash
+//  recorded in the ledger.
+//  NOTE: returns false if no change was made.
+func SyncIssue(jiraIssue Jira.Issue, repo string, lg *ledger.Ledger) bool {
+    key   := jiraIssue.Key()
+    entry := lg.Get(key)
+    prep  := PrepareIssue(jiraIssue, lg)
+    logConversion(prep)
+
+    // New Jira links are created on GitHub by linkAll() and changed
+    // properties are applied by applyAll().
+    if len(prep.Links) > 0 {
+        lg.AddLinks(key, prep.Links)
+    }
+    if len(prep.Properties) > 0 {
+        lg.SetProperties(key, prep.Properties)
+    }
+    if (prep.Hash == entry.Hash) && (len(prep.Attachments) == 0) {
+        return false
+    }
+
+    changed, failed := false, false
+    githubWriter.Do(func() {
+        changed, failed = syncWrite(prep, repo, lg, entry)
+    })
+
+    // The hash is only recorded once GitHub has every change so that a failed
+    // update is tried again on the next run.
+    lg.Update(key, func(e *ledger.Entry) {
+        if !failed {
+            e.Hash = prep.Hash
+        }
+        e.Unresolved = prep.Unresolved
+    })
+    return changed
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// A hash of the converted content of an issue which is kept up to date by
+// SyncIssue(): its title, its body and the bodies of its comments.
+func issueHash(issue *Github.IssueImport, comments []*Github.CommentImport) string {
+    bodies := make([]string, 0, len(comments))
+    for _, comment := range comments {
+        bodies = append(bodies, comment.Body)
+    }
+    return ledger.Hash(issue.Title, issue.Body, bodies)
+}
+
+// Store new attachments of a prepared issue then update its GitHub issue and
+// comments to match.
+//  NOTE: returns whether a change was made and whether any update failed.
+//  NOTE: must be run in the GitHub writer stage.
+func syncWrite(prep *PreparedIssue, repo string, lg *ledger.Ledger, entry *ledger.Entry) (changed, failed bool) {
+    key   := prep.Key
+    issue := prep.Issue
+
+    // Store new attachments then refer to the stored copy of any attachment
+    // whose content is stored under another name, as WriteIssue() does.
+    batch := newAttachmentBatch(repo, lg)
+    batch.Add(prep, true)
+    batch.Flush()
+    stored := lg.Get(key)
+    for _, attach := range prep.Attachments {
+        if !stored.HasAttachment(attach.File) {
+            failed = true
+        }
+    }
+    if renames := RepoManifest(repo, lg.Project).Renames(key); len(renames) > 0 {
+        issue.Body = renameAttachments(issue.Body, renames)
+        for _, comment := range prep.Comments {
+            comment.Body = renameAttachments(comment.Body, renames)
+        }
+    }
+
+    // Update the issue title and body if necessary.
+    client  := Github.MainClient()
+    current := Github.GetIssue(client, Github.ORG, repo, entry.Issue)
+    if current == nil {
+        logError("%s: GITHUB ISSUE %d NOT FOUND", key, entry.Issue)
+        return false, true
+    }
+    if (current.Title() != issue.Title) || (current.Body() != issue.Body) {
+        if current.EditFrom(issue.Title, issue.Body) != nil {
+            changed = true
+        } else {
+            failed = true
+        }
+    }
+
+    // Comments whose GitHub ID was not recorded when the issue was imported
+    // are matched by body or creation time; any others are new.
+    githubComments := current.Comments()
+    byID := map[int64]*Github.Comment{}
+    for _, comment := range githubComments {
+        byID[comment.ID()] = comment
+    }
+    found := matchComments(prep.Comments, prep.CommentIDs, githubComments, entry.Comments)
+    for idx, commentKey := range prep.CommentIDs {
+        body := prep.Comments[idx].Body
+        id   := entry.Comment(commentKey)
+        if id == 0 {
+            if id = found[commentKey]; id != 0 {
+                lg.AddComment(key, commentKey, id)
+            }
+        }
+        if comment := byID[id]; comment == nil {
+            if added := current.CreateCommentFrom(body); added != nil {
+                lg.AddComment(key, commentKey, added.ID())
+                changed = true
+            } else {
+                failed = true
+            }
+        } else if comment.Body() != body {
+            if comment.EditFrom(body) != nil {
+                changed = true
+            } else {
+                failed = true
+            }
+        }
+    }
+    return
+}
@@ -7,11 +7,14 @@ package main
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"lib.virginia.edu/agita/convert"
-	"lib.virginia.edu/agita/re"
+	"lib.virginia.edu/agita/ledger"
+	"lib.virginia.edu/agita/limiter"
 	"lib.virginia.edu/agita/util"
 
 	"lib.virginia.edu/agita/Github"
@@ -36,11 +39,12 @@ const PROJECT_REPOS = true
 // regardless of whether they map to a known existing GitHub repository.
 const PROJECT_REPOS_ONLY = PROJECT_REPOS && true
 
-// GitHub API accepts no more than 80 content-generating requests per minute.
-const REQUESTS_PER_MINUTE = 80
-
-// GitHub API accepts no more than 5000 requests per hour of any kind.
-const REQUESTS_PER_HOUR = 5000
+// Ledger keys of the comments holding the worklog and history tables of an
+// issue, followed by "-N" for the Nth comment of each table.
+const (
+    WORKLOG_COMMENT = "worklog"
+    HISTORY_COMMENT = "history"
+)
 
 // ============================================================================
 // Variables
@@ -49,20 +53,6 @@ const REQUESTS_PER_HOUR = 5000
 // If set, only report output is produced; no GitHub updates are made.
 var FakeTransfer bool
 
-// Time the current run was started; used to determine whether GitHub updates
-// should be throttled to avoid the primary rate limit.
-var TotalTime time.Time
-
-// Request counter used in conjuction with TotalTime.
-var TotalCount int
-
-// Time the current batch of requests was started; used to determine whether
-// GitHub updates should be throttled to avoid the secondary rate limit.
-var BatchTime time.Time
-
-// Request counter used in conjuction with BatchTime.
-var BatchCount int
-
 // ============================================================================
 // Functions
 // ============================================================================
@@ -82,14 +72,14 @@ func TransferAll(projectKeys ...string) {
     projectKeys = util.MapKeys(projIssues)
     all   := slices.Contains(projectKeys, ALL_PROJECTS)
     count := 0
+
+    // Up to CONCURRENT_PROJECTS projects are transferred at the same time.
+    var projects sync.WaitGroup
+    var countMutex sync.Mutex
+    running := make(chan struct{}, CONCURRENT_PROJECTS)
     for _, project := range Jira.MainClient().GetProjects() {
         if proj := project.Key(); all || slices.Contains(projectKeys, proj) {
-            repo, projRepo := convert.ProjectToRepo[proj], false
-            if PROJECT_REPOS_ONLY {
-                repo, projRepo = convert.ProjectRepositoryFor(proj), true
-            } else if PROJECT_REPOS && (repo == "") {
-                repo, projRepo = convert.RepositoryNameFor(proj), true
-            }
+            repo, projRepo := projectRepository(proj)
             if projRepo && !FakeTransfer {
                 if Github.GetProjRepo(Github.MainClient(), repo) == nil {
                     logError("FAILED TO GET PROJECT REPO %q", repo)
@@ -101,20 +91,34 @@ func TransferAll(projectKeys ...string) {
                 if !all {
                     minMax = projIssues[proj]
                 }
-                if count == 0 {
-                    resetSecondaryRateLimit()
-                }
-                if TransferProject(project, repo, minMax) {
-                    count++
-                }
+                running <- struct{}{}
+                projects.Add(1)
+                go func() {
+                    defer func() { <-running; projects.Done() }()
+                    if TransferProject(project, repo, minMax) {
+                        countMutex.Lock()
+                        count++
+                        countMutex.Unlock()
+                    }
+                }()
             }
         }
     }
+    projects.Wait()
+    fixAllReferences()
+    linkAll()
+    applyAll()
+    ledger.CloseAll()
     logSummary("PROJECTS TRANSFERRED: %d", count)
+    logRateLimits()
 }
 
 // Generate GitHub issues and comments from Jira issues and comments for the
 // given Jira project.
+//
+// Unless this is a fake transfer, the project ledger is consulted so that
+// issues which have already been transferred are skipped and issues whose
+// transfer failed are tried again.
 func TransferProject(project *Jira.Project, repo string, minMax []string) bool {
     var min, max string
     switch len(minMax) {
@@ -122,122 +126,111 @@ func TransferProject(project *Jira.Project, repo string, minMax []string) bool {
         case 1:  min, max = minMax[0], ""
         default: min, max = minMax[0], minMax[1]
     }
-    first, last, total := "", "", 0
-    for _, issue := range project.GetIssues(min, max) {
-        if TransferIssue(issue, repo) {
-            key := issue.Key()
-            if first == "" { first = key }
-            last = key
-            total++
-        }
+    var monitor *ImportMonitor
+    if !FakeTransfer {
+        monitor = NewImportMonitor(ledger.Open(project.Key(), repo), repo)
     }
-    logSummary("%s (%s) ISSUES TRANSFERRED: %d [%q through %q]", project.Key(), project.Name(), total, first, last)
-    return total > 0
-}
-
-// Generate GitHub issue/comments for a specific Jira issue and its comments.
-func TransferIssue(jiraIssue Jira.Issue, repo string) bool {
-    // Convert the issue.
-    key   := jiraIssue.Key()
-    issue := convert.Issue(jiraIssue)
-    issue.Body = convertAttachments(issue.Body, key)
-    logIssueFields(&jiraIssue, issue)
-
-    // Convert its related comments.
-    comments := []*Github.CommentImport{}
-    for _, fromJira := range jiraIssue.Comments() {
-        toGithub := convert.Comment(fromJira)
-        toGithub.Body = convertAttachments(toGithub.Body, key)
-        logCommentFields(&jiraIssue, &fromJira, toGithub)
-        comments = append(comments, toGithub)
+    if count := prepareMilestones(project, repo); count > 0 {
+        logSummary("%s MILESTONES CREATED OR UPDATED: %d", project.Key(), count)
     }
-
-    // Avoid updating GitHub for a fake transfer.
-    if FakeTransfer {
-        return true
+    start := time.Now()
+    stats := transferIssues(project.GetIssues(min, max), repo, monitor)
+    logSummary("%s (%s) ISSUES TRANSFERRED: %d [%q through %q]", project.Key(), project.Name(), stats.total, stats.first, stats.last)
+    if stats.skipped > 0 {
+        logSummary("%s ISSUES SKIPPED (ALREADY TRANSFERRED): %d", project.Key(), stats.skipped)
     }
-
-    // Ensure a repository destination was given.
-    if repo == "" {
-        logError("NO REPO DESTINATION FOR ISSUE %q", key)
-        return false
+    if monitor != nil {
+        reportFailed(project, monitor)
+        if monitor.ledger.LastRun().IsZero() {
+            // Later changes in Jira can be applied with "-sync".
+            monitor.ledger.SetLastRun(start)
+        }
+        if count := prepareBoards(project, repo); count > 0 {
+            logSummary("%s ISSUES PLACED ON PROJECT BOARDS: %d", project.Key(), count)
+        }
     }
-    client := Github.MainClient()
+    return stats.total > 0
+}
 
-    // Save attachments.
-    for _, attach := range jiraIssue.Attachments() {
-        checkPrimaryRateLimit()
-        file := key + "-" + attach.Filename
-        src  := Jira.DownloadAttachment(nil, attach.ID)
-        Github.CreateProjAttachment(client, repo, file, src)
+// Generate GitHub issue/comments for a specific Jira issue and its comments.
+//  NOTE: if `monitor` is nil then no GitHub updates are made.
+func TransferIssue(jiraIssue Jira.Issue, repo string, monitor *ImportMonitor) bool {
+    var lg *ledger.Ledger
+    if monitor != nil {
+        lg = monitor.ledger
     }
-
-    // Create the matching GitHub issue and comments.
-    if !checkPrimaryRateLimit() { checkSecondaryRateLimit() }
-    Github.ImportIssue(client, Github.ORG, repo, issue, comments...)
-    return true
+    prep := PrepareIssue(jiraIssue, lg)
+    return writeStage(prep, repo, monitor, nil)
 }
 
 // ============================================================================
 // Internal functions
 // ============================================================================
 
-// Replace inline Jira attachment references with GitHub references to files
-// which have been preserved in the project repository's ATTACH_DIR.
-func convertAttachments(text, issue Jira.IssueKey) string {
-    if issue != "" {
-        issue += "-"
-    }
-    root := "../blob/main/" + Github.ATTACH_DIR
-    repl := func(match string) string {
-        file := issue + re.ReplaceAll(match, `^!(.*?)(\|.*)?!$`, "$1")
-        alt  := ""
-        return fmt.Sprintf("![%s](%s/%s?raw=true)", alt, root, file)
+// The GitHub repository for the Jira project, and whether it is an
+// "issues-only" project repository.
+func projectRepository(proj string) (repo string, projRepo bool) {
+    repo, projRepo = convert.ProjectToRepo[proj], false
+    if PROJECT_REPOS_ONLY {
+        repo, projRepo = convert.ProjectRepositoryFor(proj), true
+    } else if PROJECT_REPOS && (repo == "") {
+        repo, projRepo = convert.RepositoryNameFor(proj), true
     }
-    return re.ReplaceAllFunc(text, `!\S[^!\n]+?!`, repl)
+    return
 }
 
-// Called before every GitHub request to ensure that no more than
-// REQUESTS_PER_HOUR are performed.
-func checkPrimaryRateLimit() bool {
-    if limit := Github.RateLimit(); limit.Remaining <= 1 {
-        pause := time.Until(limit.Reset.Time) + (10 * time.Second)
-        logWarning("GITHUB PRIMARY RATE LIMIT PAUSE: %v", pause)
-        time.Sleep(pause)
-        resetSecondaryRateLimit()
-        return true
-    } else {
-        return false
+// Generate the GitHub import objects for a Jira issue and its comments, along
+// with the ledger key of each comment (the Jira comment ID, or WORKLOG_COMMENT
+// or HISTORY_COMMENT with its position) and the keys of referenced Jira issues
+// which have not yet been transferred.
+//  NOTE: if `report` is not nil the field conversions are written to it.
+func convertIssue(jiraIssue Jira.Issue, report *strings.Builder) (*Github.IssueImport, []*Github.CommentImport, []string, []string) {
+    issue := convert.Issue(jiraIssue)
+    unresolved   := []string{}
+    jiraComments := jiraIssue.Comments()
+    issue.Body = convert.Attachments(issue.Body, jiraIssue, locateAttachment)
+    if links := convert.LinkedIssues(jiraIssue); links != "" {
+        issue.Body += "\n\n" + links
     }
-}
-
-// Called before every content-generating GitHub request to ensure that no more
-// than REQUESTS_PER_MINUTE are potentially pending.
-func checkSecondaryRateLimit() bool {
-    if BatchCount++; BatchCount >= REQUESTS_PER_MINUTE - 1 {
-        pause := pauseTime(time.Minute, BatchTime)
-        logWarning("GITHUB SECONDARY RATE LIMIT PAUSE: %v", pause)
-        time.Sleep(pause)
-        resetSecondaryRateLimit()
-        return true
-    } else {
-        return false
+    if attachments := convert.AttachmentList(jiraIssue, jiraComments, locateAttachment); attachments != "" {
+        issue.Body += "\n\n" + attachments
     }
+    issue.Body = convertReferences(issue.Body, &unresolved)
+    if report != nil {
+        logIssueFields(report, &jiraIssue, issue)
+    }
+    comments := []*Github.CommentImport{}
+    ids      := []string{}
+    for _, fromJira := range jiraComments {
+        toGithub := convert.Comment(fromJira)
+        toGithub.Body = convert.Attachments(toGithub.Body, jiraIssue, locateAttachment)
+        toGithub.Body = convertReferences(toGithub.Body, &unresolved)
+        if report != nil {
+            logCommentFields(report, &jiraIssue, &fromJira, toGithub)
+        }
+        comments = append(comments, toGithub)
+        ids      = append(ids, strconv.Itoa(fromJira.ID()))
+    }
+    for idx, worklog := range convert.Worklog(jiraIssue.Worklogs()) {
+        worklog.Body = convertReferences(worklog.Body, &unresolved)
+        comments = append(comments, worklog)
+        ids      = append(ids, fmt.Sprintf("%s-%d", WORKLOG_COMMENT, idx + 1))
+    }
+    for idx, history := range convert.History(jiraIssue) {
+        history.Body = convertReferences(history.Body, &unresolved)
+        comments = append(comments, history)
+        ids      = append(ids, fmt.Sprintf("%s-%d", HISTORY_COMMENT, idx + 1))
+    }
+    return issue, comments, ids, unresolved
 }
 
-// Initialize secondary rate limit values.
-func resetSecondaryRateLimit() {
-    BatchTime  = time.Now()
-    BatchCount = 0
-}
-
-// Determine a time span to wait to avoid a GitHub rate limit.  The time since
-// `start` is deducted if it is less than `pause`.
-func pauseTime(pause time.Duration, start time.Time) time.Duration {
-    if delta := time.Since(start); delta < pause {
-        return pause - delta
-    } else {
-        return pause
+// Wait for outstanding imports and report the issues whose imports failed.
+func reportFailed(project *Jira.Project, monitor *ImportMonitor) {
+    if failed := monitor.Finish(); len(failed) > 0 {
+        logSummary("%s ISSUES FAILED: %d", project.Key(), len(failed))
+        for _, key := range failed {
+            logError("%s: %s", key, monitor.ledger.Get(key).Error)
+        }
     }
 }
 
@@ -267,18 +260,34 @@ func logSummary(format string, args ...any) {
     fmt.Printf(format, args...)
 }
 
+// Report the state of the API rate limits.
+func logRateLimits() {
+    logSummary("RATE LIMITS:\n    %s: %s\n    %s: %s",
+        limiter.Github.Name, limiter.Github.State(),
+        limiter.Jira.Name,   limiter.Jira.State(),
+    )
+}
+
+// Output the field conversions of a prepared issue.
+//  NOTE: the report is buffered by PrepareIssue() so that the reports of
+//  issues prepared concurrently are output whole and in issue order.
+func logConversion(prep *PreparedIssue) {
+    if !LOG_CONVERSIONS || (prep.Report == "") { return }
+    fmt.Print(prep.Report)
+}
+
 // Report on issue field conversions.
-func logIssueFields(jira *Jira.Issue, github *Github.IssueImport) {
+func logIssueFields(report *strings.Builder, jira *Jira.Issue, github *Github.IssueImport) {
     if !LOG_CONVERSIONS { return }
     heading := fmt.Sprintf("Issue %q conversion:", jira.Key())
-    logFields(heading, jira, github, 0)
+    logFields(report, heading, jira, github, 0)
 }
 
 // Report on comment field conversions.
-func logCommentFields(issue *Jira.Issue, jira *Jira.Comment, github *Github.CommentImport) {
+func logCommentFields(report *strings.Builder, issue *Jira.Issue, jira *Jira.Comment, github *Github.CommentImport) {
     if !LOG_CONVERSIONS { return }
     heading := fmt.Sprintf("Issue %q Comment conversion:", issue.Key())
-    logFields(heading, jira, github, 4)
+    logFields(report, heading, jira, github, 4)
 }
 
 // Any type which has a Details() method.
@@ -287,7 +296,7 @@ type Details interface {
 }
 
 // Report on field conversions.
-func logFields(heading string, jira Details, github Details, indent int) {
+func logFields(report *strings.Builder, heading string, jira Details, github Details, indent int) {
     if !LOG_CONVERSIONS { return }
     parts := []string{
         "\n*** " + heading,
@@ -311,5 +320,5 @@ func logFields(heading string, jira Details, github Details, indent int) {
         }
         lines = new_lines
     }
-    fmt.Print(lines)
+    report.WriteString(lines)
 }
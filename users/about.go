@@ -0,0 +1,4 @@
+// users/about.go
+
+// Directory of the people known to Jira and/or GitHub.
+package users
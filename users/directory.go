@@ -0,0 +1,202 @@
+// users/directory.go
+//
+// The user directory, which relates each Jira account to the person's name,
+// email address and GitHub login.
+//
+// The directory is loaded from USER_FILE, which may be CSV (with a header line
+// naming the columns), JSON (an array of objects) or YAML (a list of flat
+// mappings) according to its extension.  The fields of each entry are "jira",
+// "name", "email", "github" and "status", e.g.:
+//
+//  jira,name,email,github,status
+//  abc1d,Alex Person,abc1d@virginia.edu,aperson,active
+
+package users
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lib.virginia.edu/agita/log"
+	"lib.virginia.edu/agita/util"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A user directory entry.
+type User struct {
+    Jira    string  `json:"jira"`               // Jira account.
+    Name    string  `json:"name"`               // Full name.
+    Email   string  `json:"email,omitempty"`
+    Github  string  `json:"github,omitempty"`   // GitHub login.
+    Status  string  `json:"status,omitempty"`   // STATUS_ACTIVE if blank.
+}
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// Path relative to project root of the user directory file.
+const USER_FILE = "users.csv"
+
+// User statuses.
+const (
+    STATUS_ACTIVE   = "active"      // A current user.
+    STATUS_INACTIVE = "inactive"    // A former user, who is not assigned issues.
+    STATUS_IGNORED  = "ignored"     // An account which is not a person's or is unused.
+)
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// The user directory entries in file order.
+var Directory = []*User{}
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Load the user directory from a file, replacing the current entries.
+func Load(path string) error {
+    list, err := ReadFile(path)
+    if err != nil {
+        return err
+    }
+    setDirectory(list)
+    return nil
+}
+
+// Read user directory entries from a CSV, JSON or YAML file.
+func ReadFile(path string) ([]*User, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    switch ext := strings.ToLower(filepath.Ext(path)); ext {
+        case ".csv":            return readCsv(data)
+        case ".json":           return readJson(data)
+        case ".yaml", ".yml":   return readYaml(data)
+        default:                return nil, fmt.Errorf("%s: unknown file type %q", path, ext)
+    }
+}
+
+// The directory entry for the Jira account.
+//  NOTE: returns nil if there is no such entry.
+func ByJira(account string) *User {
+    return byJira[strings.ToLower(account)]
+}
+
+// The full name of the owner of the Jira account.
+//  NOTE: returns blank if the account is not in the directory.
+func FullName(account string) string {
+    if user := ByJira(account); user != nil {
+        return user.Name
+    }
+    return ""
+}
+
+// The GitHub login of the owner of the Jira account.
+//  NOTE: returns blank if the user has no GitHub login or is not active.
+func GithubLogin(account string) string {
+    if user := ByJira(account); (user != nil) && user.Active() {
+        return user.Github
+    }
+    return ""
+}
+
+// Check directory entries for problems which can be found without consulting
+// Jira or GitHub: missing Jira accounts, unknown statuses, and duplicated Jira
+// accounts, GitHub logins or email addresses.
+//  NOTE: returns a description of each problem.
+func Check(list []*User) []string {
+    problems := []string{}
+    seen     := map[string]map[string]int{"jira": {}, "github": {}, "email": {}}
+    dup      := func(field, value string, idx int) {
+        key := strings.ToLower(value)
+        if key == "" {
+            return
+        }
+        if first, found := seen[field][key]; found {
+            problems = append(problems, fmt.Sprintf("entry %d: %s %q duplicates entry %d", idx + 1, field, value, first + 1))
+        } else {
+            seen[field][key] = idx
+        }
+    }
+    for idx, user := range list {
+        switch user.Status {
+            case "", STATUS_ACTIVE, STATUS_INACTIVE, STATUS_IGNORED: // ok
+            default:
+                problems = append(problems, fmt.Sprintf("entry %d: unknown status %q", idx + 1, user.Status))
+        }
+        if (user.Jira == "") && (user.Status != STATUS_IGNORED) {
+            problems = append(problems, fmt.Sprintf("entry %d: no Jira account for %q", idx + 1, user.Label()))
+        }
+        dup("jira",   user.Jira,   idx)
+        dup("github", user.Github, idx)
+        dup("email",  user.Email,  idx)
+    }
+    return problems
+}
+
+// ============================================================================
+// Exported methods
+// ============================================================================
+
+// Indicate whether the user is current.
+func (u *User) Active() bool {
+    return (u.Status == "") || (u.Status == STATUS_ACTIVE)
+}
+
+// A non-blank string identifying the user.
+func (u *User) Label() string {
+    for _, value := range []string{u.Jira, u.Name, u.Github, u.Email} {
+        if value != "" {
+            return value
+        }
+    }
+    return "[blank]"
+}
+
+// ============================================================================
+// Internal variables
+// ============================================================================
+
+// Directory entries by lowercase Jira account.
+var byJira = map[string]*User{}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Replace the directory entries.
+//  NOTE: for a duplicated Jira account the first entry is used.
+func setDirectory(list []*User) {
+    index := map[string]*User{}
+    for _, user := range list {
+        key := strings.ToLower(user.Jira)
+        if _, found := index[key]; (key != "") && !found {
+            index[key] = user
+        }
+    }
+    Directory = list
+    byJira    = index
+}
+
+// ============================================================================
+// Module initialization
+// ============================================================================
+
+// Load the user directory from USER_FILE.
+//  NOTE: the directory is left empty if the file does not exist.
+func init() {
+    path := filepath.Join(util.RootPath(), USER_FILE)
+    if err := Load(path); !errors.Is(err, os.ErrNotExist) {
+        log.ErrorValue(err)
+    }
+}
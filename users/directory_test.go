@@ -0,0 +1,145 @@
+// users/directory_test.go
+
+package users
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestReadFile(t *testing.T) {
+    const fn = "ReadFile"
+
+	type testCase struct {
+		name string
+		file string
+		data string
+		want int
+		err  bool
+	}
+
+    Case := func(idx int, file, data string, want int, err bool) testCase {
+        return testCase{test.CaseName(fn, idx), file, data, want, err}
+    }
+
+    dir   := t.TempDir()
+    tests := []testCase{
+        Case(0, "users.csv",  "jira,name\nabc1d,Alex Person\n",   1, false),
+        Case(1, "users.JSON", `[{"jira":"abc1d"},{"jira":"x"}]`,  2, false),
+        Case(2, "users.yml",  "- jira: abc1d\n",                  1, false),
+        Case(3, "users.yaml", "- jira: abc1d\n- jira: x\n",       2, false),
+        Case(4, "users.txt",  "abc1d\n",                          0, true),
+        Case(5, "missing.csv", "",                                0, true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(dir, tt.file)
+            if tt.data != "" {
+                if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            got, err := ReadFile(path)
+            if (err != nil) != tt.err {
+                t.Errorf("%s(%q) error = %v, want error %v", fn, tt.file, err, tt.err)
+            } else if len(got) != tt.want {
+                t.Errorf("%s(%q) = %d users, want %d", fn, tt.file, len(got), tt.want)
+            }
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+    const fn = "Check"
+
+	type testCase struct {
+		name string
+		list []*User
+		want []string
+	}
+
+    Case := func(idx int, list []*User, want ...string) testCase {
+        return testCase{test.CaseName(fn, idx), list, want}
+    }
+
+	tests := []testCase{
+        Case(0, []*User{
+            {Jira: "abc1d", Name: "Alex Person", Github: "aperson", Email: "abc1d@virginia.edu"},
+            {Jira: "pqr2s", Name: "Pat Other",   Status: STATUS_INACTIVE},
+            {Name: "Build Robot", Status: STATUS_IGNORED},
+        }),
+        Case(1, []*User{
+            {Jira: "abc1d", Status: "retired"},
+        }, `entry 1: unknown status "retired"`),
+        Case(2, []*User{
+            {Name: "Alex Person"},
+            {Github: "aperson"},
+            {},
+        }, `entry 1: no Jira account for "Alex Person"`,
+           `entry 2: no Jira account for "aperson"`,
+           `entry 3: no Jira account for "[blank]"`),
+        Case(3, []*User{
+            {Jira: "abc1d", Github: "aperson", Email: "abc1d@virginia.edu"},
+            {Jira: "ABC1D", Github: "other"},
+            {Jira: "pqr2s", Github: "APerson", Email: "ABC1D@Virginia.edu"},
+        }, `entry 2: jira "ABC1D" duplicates entry 1`,
+           `entry 3: github "APerson" duplicates entry 1`,
+           `entry 3: email "ABC1D@Virginia.edu" duplicates entry 1`),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := Check(tt.list); !slices.Equal(got, tt.want) {
+                t.Errorf("%s() = %q, want %q", fn, got, tt.want)
+            }
+		})
+	}
+}
+
+func TestByJira(t *testing.T) {
+    const fn = "ByJira"
+
+    testSetDirectory(t, []*User{
+        {Jira: "abc1d", Name: "Alex Person", Github: "aperson"},
+        {Jira: "ABC1D", Name: "Duplicate",   Github: "duplicate"},
+        {Jira: "pqr2s", Name: "Pat Other",   Github: "pother", Status: STATUS_INACTIVE},
+        {Name: "No Account"},
+    })
+
+    if got := ByJira("Abc1D"); (got == nil) || (got.Name != "Alex Person") {
+        t.Errorf("%s(%q) = %+v, want the first entry", fn, "Abc1D", got)
+    }
+    if got := ByJira(""); got != nil {
+        t.Errorf("%s(%q) = %+v, want nil", fn, "", got)
+    }
+    if got := FullName("pqr2s"); got != "Pat Other" {
+        t.Errorf("FullName() = %q, want %q", got, "Pat Other")
+    }
+    if got := GithubLogin("abc1d"); got != "aperson" {
+        t.Errorf("GithubLogin() = %q, want %q", got, "aperson")
+    }
+    if got := GithubLogin("pqr2s"); got != "" {
+        t.Errorf("GithubLogin() of inactive user = %q, want blank", got)
+    }
+    if got := GithubLogin("xyz9z"); got != "" {
+        t.Errorf("GithubLogin() of unknown user = %q, want blank", got)
+    }
+}
+
+// ============================================================================
+// Internal functions - test support
+// ============================================================================
+
+// Replace the user directory for the duration of the test.
+func testSetDirectory(t *testing.T, list []*User) {
+    saved := Directory
+    t.Cleanup(func() { setDirectory(saved) })
+    setDirectory(list)
+}
@@ -0,0 +1,135 @@
+// users/format.go
+//
+// Reading user directory files.
+
+package users
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Internal methods
+// ============================================================================
+
+// Set the named field from a CSV column or YAML mapping.
+//  NOTE: unknown fields are ignored.
+func (u *User) set(field, value string) {
+    switch strings.ToLower(field) {
+        case "jira":    u.Jira   = value
+        case "name":    u.Name   = value
+        case "email":   u.Email  = value
+        case "github":  u.Github = value
+        case "status":  u.Status = strings.ToLower(value)
+    }
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Read CSV whose first record names the columns.
+//  NOTE: lines starting with "#" are ignored.
+func readCsv(data []byte) ([]*User, error) {
+    reader := csv.NewReader(bytes.NewReader(data))
+    reader.Comment          = '#'
+    reader.FieldsPerRecord  = -1
+    reader.TrimLeadingSpace = true
+    header, err := reader.Read()
+    if err != nil {
+        return nil, err
+    }
+    res := []*User{}
+    for {
+        record, err := reader.Read()
+        if errors.Is(err, io.EOF) {
+            break
+        } else if err != nil {
+            return nil, err
+        }
+        user := &User{}
+        for col, value := range record {
+            if col < len(header) {
+                user.set(strings.TrimSpace(header[col]), strings.TrimSpace(value))
+            }
+        }
+        res = append(res, user)
+    }
+    return res, nil
+}
+
+// Read a JSON array of objects.
+func readJson(data []byte) ([]*User, error) {
+    res := []*User{}
+    if err := json.Unmarshal(data, &res); err != nil {
+        return nil, err
+    }
+    for _, user := range res {
+        user.Status = strings.ToLower(user.Status)
+    }
+    return res, nil
+}
+
+// Read YAML which is a list of flat mappings, e.g.:
+//
+//  - jira:   abc1d
+//    name:   "Alex Person"
+//    github: aperson
+//
+//  NOTE: only this subset of YAML is supported.
+func readYaml(data []byte) ([]*User, error) {
+    res := []*User{}
+    var user *User
+    for num, line := range strings.Split(string(data), "\n") {
+        text := strings.TrimSpace(line)
+        if (text == "") || (text == "---") || strings.HasPrefix(text, "#") {
+            continue
+        }
+        if rest, found := strings.CutPrefix(text, "-"); !found {
+            // A field of the current mapping.
+        } else if (rest != "") && (rest[0] != ' ') {
+            return nil, fmt.Errorf("line %d: expected a space after %q", num + 1, "-")
+        } else {
+            user = &User{}
+            res  = append(res, user)
+            if text = strings.TrimSpace(rest); text == "" {
+                continue
+            }
+        }
+        key, value, found := strings.Cut(text, ":")
+        if !found || (user == nil) {
+            return nil, fmt.Errorf("line %d: expected a list of mappings", num + 1)
+        }
+        user.set(strings.TrimSpace(key), yamlScalar(value))
+    }
+    return res, nil
+}
+
+// The value of a plain, single-quoted or double-quoted YAML scalar.
+func yamlScalar(value string) string {
+    value = strings.TrimSpace(value)
+    if (len(value) >= 2) && (value[0] == value[len(value)-1]) {
+        switch value[0] {
+            case '"':
+                if str, err := strconv.Unquote(value); err == nil {
+                    return str
+                }
+            case '\'':
+                return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+        }
+    }
+    if idx := strings.Index(value, " #"); idx >= 0 {
+        value = strings.TrimSpace(value[:idx])
+    }
+    if (value == "~") || (value == "null") {
+        return ""
+    }
+    return value
+}
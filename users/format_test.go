@@ -0,0 +1,190 @@
+// users/format_test.go
+
+package users
+
+import (
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestReadCsv(t *testing.T) {
+    const fn = "readCsv"
+
+	type testCase struct {
+		name string
+		data string
+		want []User
+		err  bool
+	}
+
+    Case := func(idx int, data string, want []User, err bool) testCase {
+        return testCase{test.CaseName(fn, idx), data, want, err}
+    }
+
+	tests := []testCase{
+        Case(0, "jira,name,email,github,status\n" +
+                "abc1d,Alex Person,abc1d@virginia.edu,aperson,Active\n",
+            []User{{"abc1d", "Alex Person", "abc1d@virginia.edu", "aperson", STATUS_ACTIVE}}, false),
+        Case(1, "# comment\n" +
+                "Name, Jira, Extra\n" +
+                "\"Person, Alex\", abc1d, x\n" +
+                "Pat Other\n",
+            []User{{Jira: "abc1d", Name: "Person, Alex"}, {Name: "Pat Other"}}, false),
+        Case(2, "jira\n", []User{}, false),
+        Case(3, "",       nil,      true),
+        Case(4, "jira,name\n\"abc1d,Alex\n", nil, true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got, err := readCsv([]byte(tt.data))
+            testVerifyUsers(fn, got, err, tt.want, tt.err, t)
+		})
+	}
+}
+
+func TestReadJson(t *testing.T) {
+    const fn = "readJson"
+
+	type testCase struct {
+		name string
+		data string
+		want []User
+		err  bool
+	}
+
+    Case := func(idx int, data string, want []User, err bool) testCase {
+        return testCase{test.CaseName(fn, idx), data, want, err}
+    }
+
+	tests := []testCase{
+        Case(0, `[{"jira":"abc1d","name":"Alex Person","github":"aperson","status":"INACTIVE"}]`,
+            []User{{Jira: "abc1d", Name: "Alex Person", Github: "aperson", Status: STATUS_INACTIVE}}, false),
+        Case(1, `[{"jira":"abc1d","unknown":1},{"name":"Pat Other"}]`,
+            []User{{Jira: "abc1d"}, {Name: "Pat Other"}}, false),
+        Case(2, `[]`,                 []User{}, false),
+        Case(3, `{"jira":"abc1d"}`,   nil,      true),
+        Case(4, `[{"jira":"abc1d"}`,  nil,      true),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got, err := readJson([]byte(tt.data))
+            testVerifyUsers(fn, got, err, tt.want, tt.err, t)
+		})
+	}
+}
+
+func TestReadYaml(t *testing.T) {
+    const fn = "readYaml"
+
+	type testCase struct {
+		name string
+		data string
+		want []User
+		err  bool
+	}
+
+    Case := func(idx int, data string, want []User, err bool) testCase {
+        return testCase{test.CaseName(fn, idx), data, want, err}
+    }
+
+	tests := []testCase{
+        Case(0, "---\n" +
+                "# users\n" +
+                "- jira:   abc1d\n" +
+                "  name:   \"Alex Person\"\n" +
+                "  github: aperson   # checked\n" +
+                "\n" +
+                "- jira: pqr2s\n" +
+                "  name: 'Pat O''Brien'\n" +
+                "  status: Ignored\n",
+            []User{
+                {Jira: "abc1d", Name: "Alex Person", Github: "aperson"},
+                {Jira: "pqr2s", Name: "Pat O'Brien", Status: STATUS_IGNORED},
+            }, false),
+        Case(1, "-\n" +
+                "  jira: abc1d\n" +
+                "  email: ~\n" +
+                "  other: value\n",
+            []User{{Jira: "abc1d"}}, false),
+        Case(2, "- jira: abc1d\n" +
+                "-jira: pqr2s\n",
+            nil, true),
+        Case(3, "jira: abc1d\n",    nil,      true),
+        Case(4, "- jira: abc1d\n" +
+                "  just text\n",
+            nil, true),
+        Case(5, "",                 []User{}, false),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got, err := readYaml([]byte(tt.data))
+            testVerifyUsers(fn, got, err, tt.want, tt.err, t)
+		})
+	}
+}
+
+func TestYamlScalar(t *testing.T) {
+    const fn = "yamlScalar"
+
+	type testCase struct {
+		name  string
+		value string
+		want  string
+	}
+
+    Case := func(idx int, value, want string) testCase {
+        return testCase{test.CaseName(fn, idx), value, want}
+    }
+
+	tests := []testCase{
+        Case(0,  " plain ",                  "plain"),
+        Case(1,  ` "double \"quoted\"" `,    `double "quoted"`),
+        Case(2,  ` 'single ''quoted''' `,    `single 'quoted'`),
+        Case(3,  "value # comment",          "value"),
+        Case(4,  "value#not comment",        "value#not comment"),
+        Case(5,  `"has # inside"`,           "has # inside"),
+        Case(6,  "~",                        ""),
+        Case(7,  "null",                     ""),
+        Case(8,  `"unterminated`,            `"unterminated`),
+        Case(9,  `'`,                        `'`),
+        Case(10, "",                         ""),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            if got := yamlScalar(tt.value); got != tt.want {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.value, got, tt.want)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Internal functions - verification
+// ============================================================================
+
+// Fail if the users read don't match the expected users.
+func testVerifyUsers(fn string, got []*User, err error, want []User, wantErr bool, t *testing.T) {
+    if wantErr {
+        if err == nil {
+            t.Errorf("%s() = %v, want error", fn, got)
+        }
+        return
+    } else if err != nil {
+        t.Errorf("%s() error = %v", fn, err)
+        return
+    }
+    if len(got) != len(want) {
+        t.Errorf("%s() = %d users, want %d", fn, len(got), len(want))
+        return
+    }
+    for idx := range want {
+        if *got[idx] != want[idx] {
+            t.Errorf("%s()[%d] = %+v, want %+v", fn, idx, *got[idx], want[idx])
+        }
+    }
+}
@@ -0,0 +1,196 @@
+// users/match.go
+//
+// Proposed directory entries for Jira accounts, found by matching the Jira
+// user's profile with the GitHub accounts which may belong to them.
+//
+// Each proposal has a confidence score from 0 (no match) to 100 (certain) so
+// that uncertain matches can be reviewed before the proposals are copied into
+// USER_FILE.  The proposal file is CSV with the columns of USER_FILE followed
+// by "confidence" and "match" (which are ignored when it is read as a user
+// directory).
+
+package users
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Exported types
+// ============================================================================
+
+// A GitHub account which may belong to a Jira user.
+type Candidate struct {
+    Login    string
+    Name     string     // Full name from the GitHub profile.
+    Email    string     // Public email address from the GitHub profile.
+    Verified []string   // Addresses in the organization's verified domains.
+}
+
+// A proposed directory entry.
+type Proposal struct {
+    User
+    Confidence int      // From 0 (no match) to 100 (certain).
+    Match      string   // How the GitHub login was matched (MATCH_...).
+}
+
+// ============================================================================
+// Exported constants
+// ============================================================================
+
+// How a GitHub login was matched with a Jira account.
+const (
+    MATCH_DIRECTORY = "directory"       // Already in the user directory.
+    MATCH_VERIFIED  = "verified email"  // A verified domain email address.
+    MATCH_EMAIL     = "public email"    // The GitHub profile email address.
+    MATCH_NAME      = "name"            // The same full name.
+    MATCH_LOGIN     = "login"           // Login same as account or email user.
+    MATCH_PARTIAL   = "partial name"    // The same first and last names.
+    MATCH_NONE      = "none"
+)
+
+// ============================================================================
+// Exported variables
+// ============================================================================
+
+// The confidence given to each kind of match.
+//  NOTE: each additional kind of match for the same login adds MATCH_BONUS.
+var MATCH_CONFIDENCE = map[string]int{
+    MATCH_DIRECTORY: 100,
+    MATCH_VERIFIED:  95,
+    MATCH_EMAIL:     85,
+    MATCH_NAME:      70,
+    MATCH_LOGIN:     60,
+    MATCH_PARTIAL:   40,
+    MATCH_NONE:      0,
+}
+
+// The confidence added for each additional kind of match.
+var MATCH_BONUS = 10
+
+// ============================================================================
+// Exported functions
+// ============================================================================
+
+// Propose a directory entry for a Jira user, whose Jira account, name, email
+// address and status are given, from the GitHub accounts which may belong to
+// them.
+//
+// An existing directory entry with a GitHub login is proposed unchanged.
+// Otherwise the candidate with the highest confidence is chosen; if several
+// candidates are equally likely the confidence is divided among them.
+//
+func Propose(user User, candidates []Candidate) *Proposal {
+    if entry := ByJira(user.Jira); (entry != nil) && (entry.Github != "") {
+        return &Proposal{User: *entry, Confidence: MATCH_CONFIDENCE[MATCH_DIRECTORY], Match: MATCH_DIRECTORY}
+    }
+    best, ties := &Proposal{User: user, Match: MATCH_NONE}, 0
+    for _, candidate := range candidates {
+        matches := matchCandidate(user, candidate)
+        if len(matches) == 0 {
+            continue
+        }
+        score := min(100, MATCH_CONFIDENCE[matches[0]] + MATCH_BONUS * (len(matches) - 1))
+        switch {
+            case score > best.Confidence:
+                best.Github, best.Confidence, best.Match = candidate.Login, score, strings.Join(matches, ", ")
+                ties = 1
+            case score == best.Confidence:
+                ties++
+        }
+    }
+    if ties > 1 {
+        best.Confidence /= ties
+        best.Match      += fmt.Sprintf(" (1 of %d)", ties)
+    }
+    if best.Name == "" {
+        best.Name = FullName(user.Jira)
+    }
+    return best
+}
+
+// Write proposals to a CSV file.
+func WriteProposals(path string, list []*Proposal) error {
+    file, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    writer := csv.NewWriter(file)
+    writer.Write([]string{"jira", "name", "email", "github", "status", "confidence", "match"})
+    for _, p := range list {
+        status := p.Status
+        if status == "" {
+            status = STATUS_ACTIVE
+        }
+        writer.Write([]string{p.Jira, p.Name, p.Email, p.Github, status, strconv.Itoa(p.Confidence), p.Match})
+    }
+    writer.Flush()
+    err = writer.Error()
+    if closeErr := file.Close(); err == nil {
+        err = closeErr
+    }
+    return err
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// The kinds of match between a Jira user and a GitHub account, from the most
+// to the least confident.
+func matchCandidate(user User, candidate Candidate) []string {
+    res   := []string{}
+    email := strings.ToLower(user.Email)
+    if email != "" {
+        for _, addr := range candidate.Verified {
+            if strings.EqualFold(addr, email) {
+                res = append(res, MATCH_VERIFIED)
+                break
+            }
+        }
+        if strings.EqualFold(candidate.Email, email) {
+            res = append(res, MATCH_EMAIL)
+        }
+    }
+    jiraName, githubName := nameWords(user.Name), nameWords(candidate.Name)
+    switch {
+        case len(jiraName) == 0, len(githubName) == 0:
+            // No name to compare.
+        case slices.Equal(jiraName, githubName):
+            res = append(res, MATCH_NAME)
+    }
+    local, _, _ := strings.Cut(email, "@")
+    login := strings.ToLower(candidate.Login)
+    if (login == strings.ToLower(user.Jira)) || (login == local) {
+        res = append(res, MATCH_LOGIN)
+    }
+    if !slices.Contains(res, MATCH_NAME) && (len(jiraName) > 1) && (len(githubName) > 1) {
+        sameFirst := jiraName[0] == githubName[0]
+        sameLast  := jiraName[len(jiraName)-1] == githubName[len(githubName)-1]
+        if sameFirst && sameLast {
+            res = append(res, MATCH_PARTIAL)
+        }
+    }
+    return res
+}
+
+// The lowercase words of a full name without punctuation.
+//  NOTE: "Last, First" is treated as "First Last".
+func nameWords(name string) []string {
+    if last, first, found := strings.Cut(name, ","); found {
+        name = first + " " + last
+    }
+    clean := func(r rune) rune {
+        if unicode.IsLetter(r) || unicode.IsSpace(r) {
+            return unicode.ToLower(r)
+        }
+        return -1
+    }
+    return strings.Fields(strings.Map(clean, name))
+}
@@ -0,0 +1,150 @@
+// users/match_test.go
+
+package users
+
+import (
+	"slices"
+	"testing"
+
+	"lib.virginia.edu/agita/test"
+)
+
+// ============================================================================
+// Tests - Exported functions
+// ============================================================================
+
+func TestPropose(t *testing.T) {
+    const fn = "Propose"
+
+	type testCase struct {
+		name       string
+		user       User
+		candidates []Candidate
+		github     string
+		confidence int
+		match      string
+	}
+
+    Case := func(idx int, user User, candidates []Candidate, github string, confidence int, match string) testCase {
+        return testCase{test.CaseName(fn, idx), user, candidates, github, confidence, match}
+    }
+
+    testSetDirectory(t, []*User{
+        {Jira: "dir1d", Name: "Listed Person", Github: "listed"},
+        {Jira: "nog1t", Name: "No Login"},
+    })
+
+    alex  := User{Jira: "abc1d", Name: "Alex Person", Email: "abc1d@virginia.edu"}
+    pat   := User{Jira: "pqr2s", Name: "Pat Q. Other"}
+    tests := []testCase{
+        Case(0, User{Jira: "dir1d"}, []Candidate{{Login: "other", Name: "Listed Person"}},
+            "listed", 100, MATCH_DIRECTORY),
+        Case(1, alex, nil, "", 0, MATCH_NONE),
+        Case(2, alex, []Candidate{
+                {Login: "abc1d", Verified: []string{"ABC1D@virginia.edu"}},
+                {Login: "alexp", Name: "Alex Person"},
+            }, "abc1d", 100, MATCH_VERIFIED + ", " + MATCH_LOGIN),
+        Case(3, alex, []Candidate{
+                {Login: "ap1", Name: "Alex Person"},
+                {Login: "ap2", Name: "Person, Alex"},
+            }, "ap1", 70 / 2, MATCH_NAME + " (1 of 2)"),
+        Case(4, alex, []Candidate{
+                {Login: "ap1", Name: "Alex Person"},
+                {Login: "ap2", Name: "Alex Person"},
+                {Login: "ap3", Name: "Alex Person", Email: "abc1d@virginia.edu"},
+            }, "ap3", 85 + MATCH_BONUS, MATCH_EMAIL + ", " + MATCH_NAME),
+        Case(5, pat, []Candidate{
+                {Login: "pother", Name: "Pat Other"},
+                {Login: "someone", Name: "Someone Else"},
+            }, "pother", 40, MATCH_PARTIAL),
+        Case(6, User{Jira: "nog1t"}, []Candidate{{Login: "nog1t"}},
+            "nog1t", 60, MATCH_LOGIN),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := Propose(tt.user, tt.candidates)
+            if got.Github != tt.github {
+                t.Errorf("%s().Github = %q, want %q", fn, got.Github, tt.github)
+            }
+            if got.Confidence != tt.confidence {
+                t.Errorf("%s().Confidence = %d, want %d", fn, got.Confidence, tt.confidence)
+            }
+            if got.Match != tt.match {
+                t.Errorf("%s().Match = %q, want %q", fn, got.Match, tt.match)
+            }
+		})
+	}
+}
+
+// ============================================================================
+// Tests - Internal functions
+// ============================================================================
+
+func TestMatchCandidate(t *testing.T) {
+    const fn = "matchCandidate"
+
+	type testCase struct {
+		name      string
+		user      User
+		candidate Candidate
+		want      []string
+	}
+
+    Case := func(idx int, user User, candidate Candidate, want ...string) testCase {
+        return testCase{test.CaseName(fn, idx), user, candidate, want}
+    }
+
+    alex  := User{Jira: "abc1d", Name: "Alex Person", Email: "alex.p@virginia.edu"}
+    tests := []testCase{
+        Case(0, alex, Candidate{Login: "someone", Name: "Someone Else"}),
+        Case(1, alex, Candidate{Login: "x", Verified: []string{"Alex.P@Virginia.edu"}}, MATCH_VERIFIED),
+        Case(2, alex, Candidate{Login: "x", Email: "alex.p@virginia.edu"}, MATCH_EMAIL),
+        Case(3, alex, Candidate{Login: "x", Name: "alex  PERSON"}, MATCH_NAME),
+        Case(4, alex, Candidate{Login: "ABC1D"}, MATCH_LOGIN),
+        Case(5, alex, Candidate{Login: "alex.p"}, MATCH_LOGIN),
+        Case(6, alex, Candidate{Login: "x", Name: "Alex J. Person"}, MATCH_PARTIAL),
+        Case(7, alex, Candidate{Login: "abc1d", Name: "Person, Alex", Email: "alex.p@virginia.edu"},
+            MATCH_EMAIL, MATCH_NAME, MATCH_LOGIN),
+        Case(8, User{Jira: "abc1d"}, Candidate{Login: "x", Email: ""}),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := matchCandidate(tt.user, tt.candidate)
+            if want := append([]string{}, tt.want...); !slices.Equal(got, want) {
+                t.Errorf("%s() = %q, want %q", fn, got, want)
+            }
+		})
+	}
+}
+
+func TestNameWords(t *testing.T) {
+    const fn = "nameWords"
+
+	type testCase struct {
+		name  string
+		value string
+		want  []string
+	}
+
+    Case := func(idx int, value string, want ...string) testCase {
+        return testCase{test.CaseName(fn, idx), value, want}
+    }
+
+	tests := []testCase{
+        Case(0, "Alex Person",         "alex", "person"),
+        Case(1, "Person, Alex",        "alex", "person"),
+        Case(2, "  Alex  J. Person ",  "alex", "j", "person"),
+        Case(3, "O'Brien, Pat",        "pat", "obrien"),
+        Case(4, "José Núñez",          "josé", "núñez"),
+        Case(5, ""),
+        Case(6, "123"),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+            got := nameWords(tt.value)
+            if want := append([]string{}, tt.want...); !slices.Equal(got, want) {
+                t.Errorf("%s(%q) = %q, want %q", fn, tt.value, got, want)
+            }
+		})
+	}
+}
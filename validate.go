@@ -0,0 +1,121 @@
+// validate.go
+//
+// Validation of the user directory against Jira and GitHub.
+
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"lib.virginia.edu/agita/users"
+	"lib.virginia.edu/agita/util"
+
+	"lib.virginia.edu/agita/Github"
+	"lib.virginia.edu/agita/Jira"
+)
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// Check the user directory and report:
+//
+// * problems with its entries (e.g. duplicated accounts),
+// * GitHub logins which do not exist or are not members of the organization,
+// * Jira accounts which appear in the given projects but not in the directory.
+//
+//  NOTE: projectKeys must have ALL_PROJECTS or a list of Jira project keys.
+func ValidateAll(projectKeys ...string) {
+    projIssues := ValidateProjectKeys(projectKeys...)
+    projectKeys = util.MapKeys(projIssues)
+    all      := slices.Contains(projectKeys, ALL_PROJECTS)
+    problems := users.Check(users.Directory)
+    problems  = append(problems, checkGithubLogins(users.Directory)...)
+    seen     := map[string]string{}
+    for _, project := range Jira.MainClient().GetProjects() {
+        if proj := project.Key(); all || slices.Contains(projectKeys, proj) {
+            minMax := []string{}
+            if !all {
+                minMax = projIssues[proj]
+            }
+            for account, key := range projectAccounts(project, minMax, false) {
+                if _, found := seen[account]; !found {
+                    seen[account] = key
+                }
+            }
+        }
+    }
+    accounts := util.MapKeys(seen)
+    slices.Sort(accounts)
+    for _, account := range accounts {
+        if users.ByJira(account) == nil {
+            problems = append(problems, "Jira account " + account + " (e.g. " + seen[account] + ") is not in the directory")
+        }
+    }
+    for _, problem := range problems {
+        logWarning("USER DIRECTORY: %s", problem)
+    }
+    logSummary("USER DIRECTORY %s: %d ENTRIES, %d JIRA ACCOUNTS SEEN, %d PROBLEMS", users.USER_FILE, len(users.Directory), len(seen), len(problems))
+}
+
+// ============================================================================
+// Internal functions
+// ============================================================================
+
+// Check that the GitHub login of each directory entry exists and, unless the
+// entry is ignored, that it is a member of the organization.
+//  NOTE: returns a description of each problem.
+func checkGithubLogins(list []*users.User) []string {
+    problems := []string{}
+    checked  := map[string]bool{}
+    for _, user := range list {
+        login := user.Github
+        if (login == "") || checked[strings.ToLower(login)] {
+            continue
+        }
+        checked[strings.ToLower(login)] = true
+        switch {
+            case !Github.UserExists(nil, login):
+                problems = append(problems, "unknown GitHub login " + login + " for " + user.Label())
+            case (user.Status != users.STATUS_IGNORED) && !Github.IsOrgMember(nil, login):
+                problems = append(problems, "GitHub login " + login + " for " + user.Label() + " is not a member of " + Github.ORG)
+        }
+    }
+    return problems
+}
+
+// The Jira accounts which are the reporter, creator or assignee of issues of
+// the project, or the authors of their comments, and optionally their
+// watchers.
+//  NOTE: returns the key of the first issue involving each account.
+//  NOTE: getting watchers requires a Jira request for each issue.
+func projectAccounts(project *Jira.Project, minMax []string, watchers bool) map[string]string {
+    var min, max string
+    switch len(minMax) {
+        case 0:  min, max = "", ""
+        case 1:  min, max = minMax[0], ""
+        default: min, max = minMax[0], minMax[1]
+    }
+    res := map[string]string{}
+    add := func(account, key string) {
+        if _, found := res[account]; (account != "") && !found {
+            res[account] = key
+        }
+    }
+    for _, issue := range project.GetIssues(min, max) {
+        key := issue.Key()
+        add(issue.Reporter(), key)
+        add(issue.Creator(),  key)
+        add(issue.Assignee(), key)
+        for _, comment := range issue.Comments() {
+            add(comment.Author(), key)
+        }
+        if watchers {
+            for _, account := range issue.Watchers() {
+                add(account, key)
+            }
+        }
+    }
+    return res
+}